@@ -1,16 +1,68 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 )
 
-func InitDB(connectionString string) (*sql.DB, error) {
-	// Membuka koneksi ke database PostgreSQL menggunakan driver "postgres".
+// DB membungkus *sql.DB bersama dialect database yang sedang dipakai.
+// Semua method *sql.DB tetap bisa dipanggil langsung karena di-embed.
+// QueryTimeout adalah batas waktu setiap operasi repository; 0 berarti tanpa batas tambahan.
+type DB struct {
+	*sql.DB
+	Dialect      Dialect
+	QueryTimeout time.Duration
+}
+
+// WithTimeout menurunkan context dengan batas waktu QueryTimeout.
+// Context yang sudah dibatalkan (misalnya client memutus koneksi) tetap membatalkan query lebih awal.
+// Pemanggil wajib memanggil cancel setelah query selesai agar resource dilepas.
+func (db *DB) WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if db.QueryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, db.QueryTimeout)
+}
+
+// Rebind menyesuaikan placeholder query dengan dialect database.
+func (db *DB) Rebind(query string) string {
+	return db.Dialect.Rebind(query)
+}
+
+// Config berisi pengaturan koneksi dan connection pool database.
+// Driver adalah "postgres" atau "sqlite". Untuk SQLite, ConnString adalah path file database, misalnya "pos.db".
+type Config struct {
+	Driver          string
+	ConnString      string
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	QueryTimeout    time.Duration
+}
+
+// InitDB membuka koneksi database sesuai cfg.Driver dan mengatur connection pool-nya.
+func InitDB(cfg Config) (*DB, error) {
+	dialect := Dialect(cfg.Driver)
+	connectionString := cfg.ConnString
+	switch dialect {
+	case Postgres:
+	case SQLite:
+		connectionString = sqliteDSN(connectionString)
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q, use %q or %q", cfg.Driver, Postgres, SQLite)
+	}
+
+	// Membuka koneksi ke database menggunakan driver yang dipilih.
 	// sql.Open tidak langsung membuat koneksi, hanya mempersiapkan.
-	db, err := sql.Open("postgres", connectionString)
+	db, err := sql.Open(string(dialect), connectionString)
 	if err != nil {
 		return nil, err
 	}
@@ -19,16 +71,37 @@ func InitDB(connectionString string) (*sql.DB, error) {
 	// Ini akan membuat koneksi fisik jika belum ada.
 	err = db.Ping()
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
-	// Mengatur pengaturan connection pool (opsional tapi direkomendasikan).
-	// SetMaxOpenConns membatasi jumlah koneksi maksimal yang terbuka.
-	db.SetMaxOpenConns(25)
-	// SetMaxIdleConns membatasi jumlah koneksi idle (tidak digunakan) yang disimpan.
-	db.SetMaxIdleConns(5)
+	// Mengatur pengaturan connection pool sesuai konfigurasi.
+	if dialect == SQLite {
+		// SQLite hanya mengizinkan satu penulis dalam satu waktu, sehingga satu koneksi
+		// mencegah error "database is locked" saat beberapa checkout berjalan bersamaan.
+		// DB_MAX_OPEN_CONNS dan DB_MAX_IDLE_CONNS diabaikan untuk SQLite.
+		db.SetMaxOpenConns(1)
+	} else {
+		// SetMaxOpenConns membatasi jumlah koneksi maksimal yang terbuka.
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+		// SetMaxIdleConns membatasi jumlah koneksi idle (tidak digunakan) yang disimpan.
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	// SetConnMaxLifetime dan SetConnMaxIdleTime memastikan koneksi lama diganti secara berkala.
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	// Mencetak pesan sukses ke log.
-	log.Println("Database connected successfully")
-	return db, nil
+	log.Printf("Database connected successfully (driver: %s)", dialect)
+	return &DB{DB: db, Dialect: dialect, QueryTimeout: cfg.QueryTimeout}, nil
+}
+
+// sqliteDSN menambahkan pragma yang dibutuhkan aplikasi ke connection string SQLite:
+// foreign key aktif (default SQLite nonaktif) dan busy timeout agar tidak langsung gagal saat file terkunci.
+func sqliteDSN(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
 }
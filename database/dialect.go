@@ -0,0 +1,50 @@
+package database
+
+import (
+	"regexp"
+)
+
+// Dialect adalah jenis database SQL yang dipakai aplikasi.
+// Query di repository ditulis dengan placeholder gaya PostgreSQL ($1, $2, ...)
+// lalu disesuaikan dengan dialect melalui Rebind.
+type Dialect string
+
+const (
+	// Postgres adalah dialect untuk PostgreSQL (driver lib/pq).
+	Postgres Dialect = "postgres"
+	// SQLite adalah dialect untuk SQLite berbasis file (driver pure-Go modernc.org/sqlite).
+	SQLite Dialect = "sqlite"
+)
+
+// placeholderPattern mencocokkan placeholder $n; tanda $ yang tidak diikuti angka bukan placeholder.
+var placeholderPattern = regexp.MustCompile(`\$(\d+)`)
+
+// Rebind mengubah placeholder $n menjadi format yang dipahami dialect.
+// PostgreSQL memakai query apa adanya, sedangkan SQLite memakai ?n
+// sehingga satu argumen tetap bisa dipakai berulang kali di dalam query.
+// Tanda $ lain, misalnya di dalam literal string, dibiarkan.
+func (d Dialect) Rebind(query string) string {
+	if d != SQLite {
+		return query
+	}
+	return placeholderPattern.ReplaceAllString(query, "?$1")
+}
+
+// ILike mengembalikan operator pencarian teks tanpa membedakan huruf besar/kecil.
+// SQLite tidak mengenal ILIKE, tetapi LIKE di SQLite sudah case-insensitive untuk huruf ASCII.
+func (d Dialect) ILike() string {
+	if d == SQLite {
+		return "LIKE"
+	}
+	return "ILIKE"
+}
+
+// ForUpdate mengembalikan klausa penguncian baris untuk SELECT di dalam transaksi, misalnya sebelum membaca
+// stok yang akan diubah. SQLite tidak mengenal FOR UPDATE, tetapi transaksinya sudah berjalan satu per satu
+// karena aplikasi hanya membuka satu koneksi SQLite.
+func (d Dialect) ForUpdate() string {
+	if d == SQLite {
+		return ""
+	}
+	return " FOR UPDATE"
+}
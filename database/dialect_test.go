@@ -0,0 +1,45 @@
+package database
+
+import "testing"
+
+func TestDialectRebind(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		query   string
+		want    string
+	}{
+		{
+			name:    "postgres query is unchanged",
+			dialect: Postgres,
+			query:   "SELECT id FROM product WHERE id = $1",
+			want:    "SELECT id FROM product WHERE id = $1",
+		},
+		{
+			name:    "sqlite placeholders keep their number",
+			dialect: SQLite,
+			query:   "UPDATE product SET stock = stock + $1 WHERE id = $2 AND stock + $1 >= 0",
+			want:    "UPDATE product SET stock = stock + ?1 WHERE id = ?2 AND stock + ?1 >= 0",
+		},
+		{
+			name:    "sqlite multi digit placeholder",
+			dialect: SQLite,
+			query:   "VALUES ($9, $10, $11)",
+			want:    "VALUES (?9, ?10, ?11)",
+		},
+		{
+			name:    "sqlite literal dollar is kept",
+			dialect: SQLite,
+			query:   "SELECT name FROM product WHERE name LIKE '%$%' AND price = $1",
+			want:    "SELECT name FROM product WHERE name LIKE '%$%' AND price = ?1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.dialect.Rebind(tt.query); got != tt.want {
+				t.Errorf("Rebind(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
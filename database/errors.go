@@ -0,0 +1,47 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+	"modernc.org/sqlite"
+)
+
+// Kode error constraint SQLite (extended result code).
+const (
+	sqliteConstraintForeignKey = 787
+	sqliteConstraintPrimaryKey = 1555
+	sqliteConstraintUnique     = 2067
+)
+
+// IsForeignKeyViolation melaporkan apakah err disebabkan pelanggaran foreign key,
+// misalnya menghapus data yang masih direferensikan tabel lain.
+func IsForeignKeyViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23503"
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code() == sqliteConstraintForeignKey
+	}
+
+	return false
+}
+
+// IsUniqueViolation melaporkan apakah err disebabkan pelanggaran unique constraint.
+func IsUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code()
+		return code == sqliteConstraintUnique || code == sqliteConstraintPrimaryKey
+	}
+
+	return false
+}
@@ -0,0 +1,297 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// migrationFiles berisi semua file migrasi SQL yang di-embed ke dalam binary.
+// Setiap dialect punya direktori sendiri (migrations/postgres, migrations/sqlite) dengan versi yang sama.
+// Nama file mengikuti format <versi>_<nama>.up.sql dan <versi>_<nama>.down.sql.
+//
+//go:embed migrations/*/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID adalah kunci advisory lock PostgreSQL yang dipakai agar
+// beberapa instance aplikasi tidak menjalankan migrasi secara bersamaan.
+const migrationLockID = 727001
+
+// Migration adalah satu versi perubahan skema beserta SQL up dan down-nya.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus menggambarkan status satu migrasi di database.
+type MigrationStatus struct {
+	Version int
+	Name    string
+	Applied bool
+}
+
+// LoadMigrations membaca semua migrasi milik dialect yang di-embed dan mengurutkannya berdasarkan versi.
+// Mengembalikan error jika ada nama file yang tidak valid atau file up/down yang hilang.
+func LoadMigrations(dialect Dialect) ([]Migration, error) {
+	dir := "migrations/" + string(dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		fileName := entry.Name()
+
+		var direction string
+		switch {
+		case strings.HasSuffix(fileName, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(fileName, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("invalid migration file name %q", fileName)
+		}
+
+		base := strings.TrimSuffix(fileName, "."+direction+".sql")
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", fileName)
+		}
+		version, err := strconv.Atoi(versionStr)
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %q", fileName)
+		}
+
+		content, err := migrationFiles.ReadFile(dir + "/" + fileName)
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration version %d has conflicting names %q and %q", version, m.Name, name)
+		}
+
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down files", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// LatestVersion mengembalikan versi migrasi tertinggi untuk dialect yang di-embed di binary.
+func LatestVersion(dialect Dialect) (int, error) {
+	migrations, err := LoadMigrations(dialect)
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, nil
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// SchemaVersion mengembalikan versi migrasi tertinggi yang sudah diterapkan di database.
+// Fungsi ini hanya membaca dan tidak membuat tabel schema_migrations,
+// sehingga aman dipanggil berulang kali (misalnya dari readiness probe).
+// Jika tabel belum ada, error dari database dikembalikan apa adanya.
+func SchemaVersion(ctx context.Context, db *DB) (int, error) {
+	var version int
+	err := db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	return version, err
+}
+
+// Migrate menerapkan semua migrasi up yang belum dijalankan secara berurutan.
+// Setiap migrasi berjalan di dalam transaksi sendiri bersama pencatatan versinya.
+func Migrate(ctx context.Context, db *DB) error {
+	migrations, err := LoadMigrations(db.Dialect)
+	if err != nil {
+		return err
+	}
+
+	return withMigrationLock(ctx, db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range migrations {
+			if applied[m.Version] {
+				continue
+			}
+
+			err := runMigration(ctx, conn, m.Up,
+				db.Rebind("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)"), m.Version, m.Name)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", m.Version, m.Name, err)
+			}
+			log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+		}
+		return nil
+	})
+}
+
+// MigrateDown membatalkan migrasi terakhir sebanyak steps secara berurutan dari versi tertinggi.
+func MigrateDown(ctx context.Context, db *DB, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("steps must be positive, got %d", steps)
+	}
+
+	migrations, err := LoadMigrations(db.Dialect)
+	if err != nil {
+		return err
+	}
+
+	return withMigrationLock(ctx, db, func(conn *sql.Conn) error {
+		applied, err := appliedVersions(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(migrations) - 1; i >= 0 && steps > 0; i-- {
+			m := migrations[i]
+			if !applied[m.Version] {
+				continue
+			}
+
+			err := runMigration(ctx, conn, m.Down,
+				db.Rebind("DELETE FROM schema_migrations WHERE version = $1"), m.Version)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", m.Version, m.Name, err)
+			}
+			log.Printf("Reverted migration %04d_%s", m.Version, m.Name)
+			steps--
+		}
+		return nil
+	})
+}
+
+// Status mengembalikan daftar semua migrasi yang di-embed beserta status penerapannya.
+func Status(ctx context.Context, db *DB) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations(db.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	applied, err := appliedVersions(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		statuses = append(statuses, MigrationStatus{
+			Version: m.Version,
+			Name:    m.Name,
+			Applied: applied[m.Version],
+		})
+	}
+	return statuses, nil
+}
+
+// withMigrationLock mengambil satu koneksi khusus, mengunci advisory lock (khusus PostgreSQL),
+// lalu menjalankan fn. Lock dilepas setelah fn selesai.
+// SQLite tidak membutuhkan lock tambahan karena hanya mengizinkan satu penulis.
+func withMigrationLock(ctx context.Context, db *DB, fn func(conn *sql.Conn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if db.Dialect != Postgres {
+		return fn(conn)
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	return fn(conn)
+}
+
+// ensureMigrationTable membuat tabel schema_migrations jika belum ada.
+func ensureMigrationTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			name       TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+// appliedVersions mengembalikan set versi migrasi yang sudah tercatat di schema_migrations.
+func appliedVersions(ctx context.Context, conn *sql.Conn) (map[int]bool, error) {
+	if err := ensureMigrationTable(ctx, conn); err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// runMigration menjalankan script migrasi dan query pencatatan versi di dalam satu transaksi.
+func runMigration(ctx context.Context, conn *sql.Conn, script, record string, args ...interface{}) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS product (
+    id          SERIAL PRIMARY KEY,
+    name        VARCHAR(150) NOT NULL,
+    price       INTEGER NOT NULL DEFAULT 0,
+    stock       INTEGER NOT NULL DEFAULT 0,
+    category_id INTEGER NOT NULL REFERENCES category (id)
+);
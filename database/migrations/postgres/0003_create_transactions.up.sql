@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS transactions (
+    id           SERIAL PRIMARY KEY,
+    total_amount INTEGER NOT NULL DEFAULT 0,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS transaction_details (
+    id             SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions (id) ON DELETE CASCADE,
+    product_id     INTEGER NOT NULL REFERENCES product (id),
+    quantity       INTEGER NOT NULL,
+    subtotal       INTEGER NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_created_at ON transactions (created_at);
+CREATE INDEX IF NOT EXISTS idx_transaction_details_transaction_id ON transaction_details (transaction_id);
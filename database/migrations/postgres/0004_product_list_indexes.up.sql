@@ -0,0 +1,4 @@
+-- Index untuk pengurutan dan paginasi keyset pada GET /api/product.
+CREATE INDEX IF NOT EXISTS idx_product_name_id ON product (name, id);
+CREATE INDEX IF NOT EXISTS idx_product_price_id ON product (price, id);
+CREATE INDEX IF NOT EXISTS idx_product_stock_id ON product (stock, id);
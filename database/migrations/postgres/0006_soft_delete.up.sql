@@ -0,0 +1,2 @@
+ALTER TABLE category ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
+ALTER TABLE product ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP NULL;
@@ -0,0 +1,6 @@
+ALTER TABLE product ADD COLUMN IF NOT EXISTS sku VARCHAR(64) NULL;
+ALTER TABLE product ADD COLUMN IF NOT EXISTS barcode VARCHAR(13) NULL;
+-- SKU dan barcode hanya unik di antara produk aktif, sehingga kode produk yang sudah dihapus bisa dipakai lagi.
+-- Index barcode juga dipakai untuk GET /api/product/barcode/{code} dan checkout dengan barcode.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_sku ON product (sku) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_barcode ON product (barcode) WHERE deleted_at IS NULL;
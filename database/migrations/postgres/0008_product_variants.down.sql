@@ -0,0 +1,2 @@
+ALTER TABLE transaction_details DROP COLUMN IF EXISTS variant_id;
+DROP TABLE IF EXISTS product_variant;
@@ -0,0 +1,5 @@
+-- Extension pg_trgm tidak dihapus karena mungkin dipakai objek lain di database yang sama.
+DROP INDEX IF EXISTS idx_category_name_trgm;
+DROP INDEX IF EXISTS idx_product_name_trgm;
+DROP INDEX IF EXISTS idx_product_search_vector;
+ALTER TABLE product DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,11 @@
+-- Pencarian produk berperingkat (GET /api/product?q= dan GET /api/product/suggest).
+-- search_vector berisi kata dari nama dan SKU produk untuk pencocokan awalan kata dengan tsquery,
+-- sedangkan index trigram (pg_trgm) menoleransi salah ketik di nama produk dan nama kategori.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE product ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (to_tsvector('simple'::regconfig, name || ' ' || COALESCE(sku, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_product_search_vector ON product USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_product_name_trgm ON product USING GIN (name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_category_name_trgm ON category USING GIN (name gin_trgm_ops);
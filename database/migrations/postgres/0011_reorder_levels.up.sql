@@ -0,0 +1,4 @@
+-- Batas stok untuk peringatan stok menipis. reorder_level 0 berarti peringatan nonaktif;
+-- reorder_qty adalah jumlah yang disarankan untuk dipesan ulang.
+ALTER TABLE product ADD COLUMN IF NOT EXISTS reorder_level INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE product ADD COLUMN IF NOT EXISTS reorder_qty INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS supplier (
+    id           SERIAL PRIMARY KEY,
+    name         VARCHAR(150) NOT NULL,
+    contact_name VARCHAR(100) NOT NULL DEFAULT '',
+    phone        VARCHAR(32) NOT NULL DEFAULT '',
+    email        VARCHAR(254) NOT NULL DEFAULT ''
+);
+
+-- Purchase order ke supplier. status: ordered, partially_received, received, atau cancelled.
+CREATE TABLE IF NOT EXISTS purchase_order (
+    id          SERIAL PRIMARY KEY,
+    supplier_id INTEGER NOT NULL REFERENCES supplier (id),
+    status      VARCHAR(32) NOT NULL DEFAULT 'ordered',
+    note        TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_status ON purchase_order (status, id);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_supplier_id ON purchase_order (supplier_id, id);
+
+-- Baris purchase order. received_quantity bertambah setiap kali barang diterima dan tidak pernah melebihi quantity.
+CREATE TABLE IF NOT EXISTS purchase_order_item (
+    id                SERIAL PRIMARY KEY,
+    purchase_order_id INTEGER NOT NULL REFERENCES purchase_order (id) ON DELETE CASCADE,
+    product_id        INTEGER NOT NULL REFERENCES product (id),
+    variant_id        INTEGER NULL REFERENCES product_variant (id),
+    quantity          INTEGER NOT NULL,
+    received_quantity INTEGER NOT NULL DEFAULT 0,
+    unit_cost         INTEGER NOT NULL,
+    CHECK (received_quantity >= 0 AND received_quantity <= quantity)
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_order_item_purchase_order_id ON purchase_order_item (purchase_order_id, id);
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS category (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_product_stock_id;
+DROP INDEX IF EXISTS idx_product_price_id;
+DROP INDEX IF EXISTS idx_product_name_id;
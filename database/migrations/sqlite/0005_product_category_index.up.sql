@@ -0,0 +1,2 @@
+-- Index untuk GET /api/category/{id}/products dan perhitungan product_count per kategori.
+CREATE INDEX IF NOT EXISTS idx_product_category_id ON product (category_id, id);
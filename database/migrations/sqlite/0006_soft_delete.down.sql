@@ -0,0 +1,2 @@
+ALTER TABLE product DROP COLUMN deleted_at;
+ALTER TABLE category DROP COLUMN deleted_at;
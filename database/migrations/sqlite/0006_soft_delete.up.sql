@@ -0,0 +1,2 @@
+ALTER TABLE category ADD COLUMN deleted_at TIMESTAMP NULL;
+ALTER TABLE product ADD COLUMN deleted_at TIMESTAMP NULL;
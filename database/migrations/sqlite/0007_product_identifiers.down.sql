@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_product_barcode;
+DROP INDEX IF EXISTS idx_product_sku;
+ALTER TABLE product DROP COLUMN barcode;
+ALTER TABLE product DROP COLUMN sku;
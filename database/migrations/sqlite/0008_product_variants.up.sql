@@ -0,0 +1,17 @@
+-- Varian produk. options berisi JSON objek dengan key terurut (misalnya {"color":"red","size":"M"}),
+-- sehingga kombinasi opsi yang sama selalu menghasilkan teks yang sama untuk unique index.
+CREATE TABLE IF NOT EXISTS product_variant (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id INTEGER NOT NULL REFERENCES product (id),
+    sku        TEXT NULL,
+    options    TEXT NOT NULL,
+    price      INTEGER NULL,
+    stock      INTEGER NOT NULL DEFAULT 0,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_variant_product_id ON product_variant (product_id, id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variant_sku ON product_variant (sku) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_variant_options ON product_variant (product_id, options) WHERE deleted_at IS NULL;
+
+ALTER TABLE transaction_details ADD COLUMN variant_id INTEGER NULL REFERENCES product_variant (id);
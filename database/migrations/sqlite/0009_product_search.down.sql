@@ -0,0 +1,2 @@
+-- Tidak ada perubahan skema untuk SQLite (lihat 0009_product_search.up.sql).
+SELECT 1;
@@ -0,0 +1,3 @@
+-- SQLite tidak punya tsvector dan pg_trgm. Pencarian produk memakai fungsi search_score yang didaftarkan
+-- aplikasi (database/sqlite.go), sehingga migrasi ini hanya menjaga nomor versi tetap sama dengan PostgreSQL.
+SELECT 1;
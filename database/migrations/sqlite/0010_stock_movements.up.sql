@@ -0,0 +1,22 @@
+-- Ledger setiap perubahan stok produk (variant_id NULL) dan varian. Jumlah delta per produk atau varian
+-- harus sama dengan kolom stock-nya; periksa dengan "go run . stock reconcile".
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    product_id  INTEGER NOT NULL REFERENCES product (id),
+    variant_id  INTEGER NULL REFERENCES product_variant (id),
+    delta       INTEGER NOT NULL,
+    stock_after INTEGER NOT NULL,
+    reason      TEXT NOT NULL,
+    reference   TEXT NULL,
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_product_id ON stock_movements (product_id, id);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_variant_id ON stock_movements (variant_id, id);
+
+-- Stok yang sudah ada sebelum ledger dibuat dicatat sebagai saldo awal.
+INSERT INTO stock_movements (product_id, variant_id, delta, stock_after, reason)
+SELECT id, NULL, stock, stock, 'opening_balance' FROM product WHERE stock <> 0;
+
+INSERT INTO stock_movements (product_id, variant_id, delta, stock_after, reason)
+SELECT product_id, id, stock, stock, 'opening_balance' FROM product_variant WHERE stock <> 0;
@@ -0,0 +1,2 @@
+ALTER TABLE product DROP COLUMN reorder_qty;
+ALTER TABLE product DROP COLUMN reorder_level;
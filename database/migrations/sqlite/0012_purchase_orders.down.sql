@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS purchase_order_item;
+DROP TABLE IF EXISTS purchase_order;
+DROP TABLE IF EXISTS supplier;
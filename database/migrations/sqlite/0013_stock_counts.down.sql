@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS stock_count_item;
+DROP TABLE IF EXISTS stock_count;
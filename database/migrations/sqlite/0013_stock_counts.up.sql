@@ -0,0 +1,28 @@
+-- Sesi stock opname (penghitungan stok fisik). status: open, posted, atau cancelled.
+-- category_id NULL berarti sesi menghitung produk dari semua kategori.
+CREATE TABLE IF NOT EXISTS stock_count (
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    status      TEXT NOT NULL DEFAULT 'open',
+    category_id INTEGER NULL REFERENCES category (id),
+    note        TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    posted_at   TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_count_status ON stock_count (status, id);
+-- Hanya satu sesi yang boleh open, agar selisih produk yang sama tidak diposting dua kali.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stock_count_open ON stock_count (status) WHERE status = 'open';
+
+-- Baris sesi stock opname. system_quantity adalah stok saat sesi dibuka dan counted_quantity hasil hitung fisik
+-- (NULL jika belum dihitung).
+CREATE TABLE IF NOT EXISTS stock_count_item (
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    stock_count_id   INTEGER NOT NULL REFERENCES stock_count (id) ON DELETE CASCADE,
+    product_id       INTEGER NOT NULL REFERENCES product (id),
+    variant_id       INTEGER NULL REFERENCES product_variant (id),
+    system_quantity  INTEGER NOT NULL,
+    counted_quantity INTEGER NULL CHECK (counted_quantity >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_count_item_stock_count_id ON stock_count_item (stock_count_id, id);
@@ -3,8 +3,6 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
-	"strings"
 	"task-session-1/models"
 	"task-session-1/services"
 )
@@ -21,146 +19,184 @@ func NewCategoryHandler(service *services.CategoryService) *CategoryHandler {
 	return &CategoryHandler{service: service}
 }
 
-// HandleCategory menangani request ke /api/category.
-// Berdasarkan metode HTTP (GET untuk GetAll, POST untuk Create).
-// Jika metode tidak didukung, kembalikan error 405 Method Not Allowed.
-func (h *CategoryHandler) HandleCategory(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.GetAll(w, r)
-	case http.MethodPost:
-		h.Create(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// GetAll menangani GET /api/category untuk mengambil daftar kategori.
+// Filter nama, pengurutan, dan paginasi dibaca dari query string (lihat parseCategoryFilter).
+// Kategori yang sudah dihapus hanya ikut ditampilkan dengan ?include_deleted=true dari admin.
+// Response berupa Page: {"items": [...], "next_cursor": "...", "total": n}.
+// Jika ada error, kirim response error JSON melalui writeError.
+func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseCategoryFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !checkIncludeDeleted(w, r, filter.IncludeDeleted) {
+		return
 	}
-}
 
-// GetAll menangani GET /api/category untuk mengambil semua kategori.
-// Memanggil service.GetAll(), lalu encode hasil ke JSON dan kirim sebagai response.
-// Jika ada error, kembalikan status 500 Internal Server Error.
-func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	category, err := h.service.GetAll()
+	page, err := h.service.GetAll(r.Context(), filter)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(category)
+	writeJSON(w, http.StatusOK, page)
 }
 
 // Create menangani POST /api/category untuk membuat kategori baru.
 // Decode JSON dari request body ke struct Category.
 // Panggil service.Create(), lalu encode hasil ke JSON dan kirim sebagai response dengan status 201 Created.
-// Jika ada error, kembalikan status 400 Bad Request.
+// Jika body tidak valid, kembalikan status 400 Bad Request; error lain dipetakan oleh writeError.
 func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var category models.Category
 	err := json.NewDecoder(r.Body).Decode(&category)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
 		return
 	}
 
-	err = h.service.Create(&category)
+	err = h.service.Create(r.Context(), &category)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(category)
-}
-
-// HandleCategoryByID menangani request ke /api/category/{id}.
-// Berdasarkan metode HTTP (GET untuk GetByID, PUT untuk Update, DELETE untuk Delete).
-// Jika metode tidak didukung, kembalikan error 405 Method Not Allowed.
-func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.GetByID(w, r)
-	case http.MethodPut:
-		h.Update(w, r)
-	case http.MethodDelete:
-		h.Delete(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
+	writeJSON(w, http.StatusCreated, category)
 }
 
 // GetByID menangani GET /api/category/{id} untuk mengambil kategori berdasarkan ID.
 // Ekstrak ID dari URL path, konversi ke int.
 // Panggil service.GetByID(), lalu encode hasil ke JSON.
 // Jika ID invalid, kembalikan status 400 Bad Request.
-// Jika kategori tidak ditemukan, kembalikan status 404 Not Found.
+// Jika kategori tidak ditemukan atau sudah dihapus, kembalikan status 404 Not Found,
+// kecuali admin mengirim ?include_deleted=true.
 func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/category/")
-	id, err := strconv.Atoi(idStr)
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid category id", nil)
+		return
+	}
+
+	opts, err := parseCategoryOptions(r.URL.Query())
 	if err != nil {
-		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+	if !checkIncludeDeleted(w, r, opts.IncludeDeleted) {
 		return
 	}
 
-	category, err := h.service.GetByID(id)
+	category, err := h.service.GetByID(r.Context(), id, opts)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(category)
+	writeJSON(w, http.StatusOK, category)
 }
 
 // Update menangani PUT /api/category/{id} untuk memperbarui kategori.
 // Ekstrak ID dari URL, decode JSON dari body.
 // Set ID ke category, panggil service.Update(), lalu encode hasil ke JSON.
-// Jika ada error, kembalikan status 400 Bad Request.
+// Jika kategori tidak ditemukan, kembalikan status 404 Not Found.
 func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/category/")
-	id, err := strconv.Atoi(idStr)
-	if err != nil {
-		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid category id", nil)
 		return
 	}
 
 	var category models.Category
-	err = json.NewDecoder(r.Body).Decode(&category)
+	err := json.NewDecoder(r.Body).Decode(&category)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
 		return
 	}
 
 	category.ID = id
-	err = h.service.Update(&category)
+	err = h.service.Update(r.Context(), &category)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(category)
+	writeJSON(w, http.StatusOK, category)
 }
 
-// Delete menangani DELETE /api/category/{id} untuk menghapus kategori.
-// Ekstrak ID dari URL, panggil service.Delete().
-// Jika berhasil, kirim response JSON dengan pesan sukses.
-// Jika ada error, kembalikan status 500 Internal Server Error.
+// Patch menangani PATCH /api/category/{id} dengan semantik JSON Merge Patch.
+// Hanya field yang dikirim (name, description) yang diubah, dan null pada description mengosongkannya,
+// lalu kategori setelah diperbarui dikirim sebagai response.
+func (h *CategoryHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid category id", nil)
+		return
+	}
+
+	var patch models.CategoryPatch
+	err := decodeMergePatch(r, map[string]interface{}{
+		"name":        &patch.Name,
+		"description": nullable(&patch.Description),
+	})
+	if err != nil {
+		writePatchError(w, r, err)
+		return
+	}
+
+	category, err := h.service.Patch(r.Context(), id, patch)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, category)
+}
+
+// Delete menangani DELETE /api/category/{id} untuk menghapus kategori secara soft delete.
+// Parameter ?mode= menentukan nasib produk aktif di kategori tersebut:
+// restrict (default, 409 Conflict jika masih ada produk), reassign (pindah ke ?target_category_id=),
+// atau cascade (produk ikut dihapus). Response berisi jumlah produk yang dipindahkan atau ikut dihapus.
+// Jika kategori tidak ditemukan, kembalikan status 404.
 func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/category/")
-	id, err := strconv.Atoi(idStr)
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid category id", nil)
+		return
+	}
+
+	policy, err := parseCategoryDeletePolicy(r.URL.Query())
 	if err != nil {
-		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	err = h.service.Delete(id)
+	affected, err := h.service.Delete(r.Context(), id, policy)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
-		"message": "category deleted successfully",
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":           "category deleted successfully",
+		"mode":              policy.Mode,
+		"affected_products": affected,
 	})
 }
+
+// Restore menangani POST /api/category/{id}/restore untuk mengembalikan kategori yang sudah dihapus.
+// Kategori setelah dipulihkan dikirim sebagai response.
+func (h *CategoryHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid category id", nil)
+		return
+	}
+
+	category, err := h.service.Restore(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, category)
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"task-session-1/database"
+	"time"
+)
+
+// readinessTimeout adalah batas waktu pengecekan database pada /readyz,
+// agar load balancer tidak menunggu lama saat database bermasalah.
+const readinessTimeout = 2 * time.Second
+
+// HealthHandler menangani endpoint liveness, readiness, dan diagnostik.
+// db bernilai nil jika aplikasi berjalan dengan storage in-memory.
+// configSummary adalah ringkasan konfigurasi yang rahasianya sudah disamarkan.
+type HealthHandler struct {
+	db            *database.DB
+	version       string
+	startedAt     time.Time
+	configSummary map[string]interface{}
+}
+
+// NewHealthHandler adalah konstruktor untuk membuat instance HealthHandler.
+func NewHealthHandler(db *database.DB, version string, configSummary map[string]interface{}) *HealthHandler {
+	return &HealthHandler{
+		db:            db,
+		version:       version,
+		startedAt:     time.Now(),
+		configSummary: configSummary,
+	}
+}
+
+// Healthz menangani GET /healthz.
+// Hanya menandakan proses masih hidup, tanpa mengecek dependency apa pun.
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"status": "ok",
+	})
+}
+
+// Readyz menangani GET /readyz.
+// Service dianggap siap jika database bisa di-ping dalam readinessTimeout
+// dan versi skema database sama dengan versi migrasi terbaru di binary.
+// Jika tidak siap, response dikirim dengan status 503 Service Unavailable.
+// Endpoint ini tidak memakai autentikasi, sehingga hanya status tetap yang dikirim; detail error
+// dicatat di log dan bisa dilihat di /debug/status.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	checks := make(map[string]string)
+	ready := true
+
+	if h.db == nil {
+		checks["database"] = "skipped (in-memory storage)"
+	} else {
+		for name, check := range h.runChecks(r.Context()) {
+			checks[name] = check.status
+			if check.err != nil {
+				slog.WarnContext(r.Context(), "readiness check failed", "check", name, "request_id", requestID(r), "error", check.err)
+				ready = false
+			}
+		}
+	}
+
+	status := "ready"
+	code := http.StatusOK
+	if !ready {
+		status = "not ready"
+		code = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, code, map[string]interface{}{
+		"status": status,
+		"checks": checks,
+	})
+}
+
+// readinessCheck adalah hasil satu pengecekan readiness.
+// status adalah nilai tetap yang aman ditampilkan tanpa autentikasi, sedangkan err berisi detail kegagalan.
+type readinessCheck struct {
+	status string
+	err    error
+}
+
+// runChecks mengecek koneksi dan versi skema database dalam readinessTimeout.
+func (h *HealthHandler) runChecks(ctx context.Context) map[string]readinessCheck {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	checks := map[string]readinessCheck{"database": {status: "ok"}}
+	if err := h.db.PingContext(ctx); err != nil {
+		checks["database"] = readinessCheck{status: "unreachable", err: err}
+	}
+	checks["migrations"] = h.checkMigrations(ctx)
+	return checks
+}
+
+// checkMigrations membandingkan versi skema di database dengan versi migrasi terbaru.
+func (h *HealthHandler) checkMigrations(ctx context.Context) readinessCheck {
+	expected, err := database.LatestVersion(h.db.Dialect)
+	if err != nil {
+		return readinessCheck{status: "error", err: fmt.Errorf("cannot load migrations: %w", err)}
+	}
+
+	current, err := database.SchemaVersion(ctx, h.db)
+	if err != nil {
+		return readinessCheck{status: "error", err: fmt.Errorf("cannot read schema version: %w", err)}
+	}
+
+	if current != expected {
+		return readinessCheck{status: "outdated", err: fmt.Errorf("schema at version %d, expected %d", current, expected)}
+	}
+	return readinessCheck{status: "ok"}
+}
+
+// DebugStatus menangani GET /debug/status.
+// Menampilkan statistik connection pool, versi build, uptime, serta ringkasan konfigurasi tanpa rahasia.
+// Hasil pengecekan readiness ditampilkan lengkap dengan detail error-nya.
+// Route ini dilindungi middleware RequireAdmin sehingga hanya bisa diakses dengan ADMIN_TOKEN.
+func (h *HealthHandler) DebugStatus(w http.ResponseWriter, r *http.Request) {
+	status := map[string]interface{}{
+		"version":    h.version,
+		"started_at": h.startedAt,
+		"uptime":     time.Since(h.startedAt).Round(time.Second).String(),
+		"config":     h.configSummary,
+	}
+
+	if h.db != nil {
+		stats := h.db.Stats()
+		status["database"] = map[string]interface{}{
+			"driver":               h.db.Dialect,
+			"max_open_connections": stats.MaxOpenConnections,
+			"open_connections":     stats.OpenConnections,
+			"in_use":               stats.InUse,
+			"idle":                 stats.Idle,
+			"wait_count":           stats.WaitCount,
+			"wait_duration":        stats.WaitDuration.String(),
+			"max_idle_closed":      stats.MaxIdleClosed,
+			"max_idle_time_closed": stats.MaxIdleTimeClosed,
+			"max_lifetime_closed":  stats.MaxLifetimeClosed,
+		}
+
+		checks := make(map[string]string)
+		for name, check := range h.runChecks(r.Context()) {
+			checks[name] = check.status
+			if check.err != nil {
+				checks[name] += ": " + check.err.Error()
+			}
+		}
+		status["checks"] = checks
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Middleware membungkus http.Handler dengan perilaku tambahan,
+// misalnya logging, recovery dari panic, atau pengecekan otorisasi.
+type Middleware func(http.Handler) http.Handler
+
+// Chain memasang middleware ke handler dengan urutan sesuai parameter:
+// middleware pertama adalah lapisan terluar dan dijalankan paling awal.
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// requestIDHeader adalah header yang dipakai untuk menerima dan mengirim ID request.
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength membatasi panjang ID request dari client agar log tidak dibanjiri data.
+const maxRequestIDLength = 128
+
+// requestIDKey adalah key context untuk menyimpan ID request.
+type requestIDKey struct{}
+
+// RequestID memastikan setiap request punya ID.
+// ID dari header X-Request-ID dipakai jika valid, selain itu dibuat ID acak baru.
+// ID disimpan di context dan dikirim kembali di header response.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(requestIDHeader, id)
+		ctx := context.WithValue(r.Context(), requestIDKey{}, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RequestIDFromContext mengambil ID request yang disimpan oleh middleware RequestID.
+// Mengembalikan string kosong jika context tidak punya ID request.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID memeriksa bahwa ID dari client tidak kosong, tidak terlalu panjang,
+// dan hanya berisi karakter ASCII yang bisa dicetak.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID membuat ID request acak berupa 16 byte dalam format hex.
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder mencatat status code dan jumlah byte yang ditulis ke response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (rec *statusRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// Unwrap mengembalikan ResponseWriter asli agar http.ResponseController tetap bisa
+// memakai fitur seperti Flush dari balik recorder.
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// Logging mencatat setiap request beserta status, ukuran response, durasi, dan ID request.
+func Logging(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		slog.InfoContext(r.Context(), "request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", status,
+			"bytes", rec.bytes,
+			"duration", time.Since(start),
+			"request_id", RequestIDFromContext(r.Context()),
+		)
+	})
+}
+
+// Recovery menangkap panic di handler, mencatat stack trace ke log,
+// lalu mengirim response 500 agar server tetap berjalan.
+// http.ErrAbortHandler diteruskan karena memang dipakai untuk membatalkan response.
+func Recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			slog.ErrorContext(r.Context(), "panic recovered",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"panic", rec,
+				"request_id", RequestIDFromContext(r.Context()),
+				"stack", string(debug.Stack()),
+			)
+			writeErrorResponse(w, r, http.StatusInternalServerError, codeInternal, "internal server error", nil)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// adminKey adalah key context untuk menandai request yang membawa token admin yang valid.
+type adminKey struct{}
+
+// IdentifyAdmin menandai request yang membawa bearer token admin yang valid tanpa menolak request lain,
+// untuk endpoint publik yang punya opsi khusus admin (misalnya ?include_deleted=true).
+func IdentifyAdmin(token string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if validBearer(r, token) {
+				r = r.WithContext(context.WithValue(r.Context(), adminKey{}, true))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IsAdmin mengembalikan true jika request sudah ditandai sebagai admin oleh IdentifyAdmin.
+func IsAdmin(ctx context.Context) bool {
+	admin, _ := ctx.Value(adminKey{}).(bool)
+	return admin
+}
+
+// RequireAdmin adalah hook otorisasi untuk endpoint admin.
+// Request harus membawa header "Authorization: Bearer <token>" yang dibandingkan secara constant-time.
+// Jika token kosong, semua request ditolak sehingga endpoint admin nonaktif.
+func RequireAdmin(token string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !validBearer(r, token) {
+				writeAdminRequired(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// writeAdminRequired mengirim response 401 untuk request yang butuh token admin.
+func writeAdminRequired(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	writeErrorResponse(w, r, http.StatusUnauthorized, codeUnauthorized, "missing or invalid admin token", nil)
+}
+
+// validBearer memeriksa bearer token di header Authorization terhadap token yang diharapkan.
+func validBearer(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"task-session-1/models"
+)
+
+// errInvalidBody menandakan body request bukan JSON yang valid.
+var errInvalidBody = errors.New("invalid request body")
+
+// nullableField menandai field patch opsional di decodeMergePatch: nilai null mengosongkan field tersebut.
+type nullableField struct {
+	target interface{}
+}
+
+// nullable membungkus pointer field patch opsional, misalnya nullable(&patch.SKU), agar null pada field itu
+// diterima sebagai perintah mengosongkan (RFC 7396) dan diubah menjadi nilai nol tipenya, misalnya "" atau 0.
+func nullable(target interface{}) nullableField {
+	return nullableField{target: target}
+}
+
+// decodeMergePatch membaca body JSON Merge Patch (RFC 7396, application/merge-patch+json) ke field-field patch.
+// Seperti endpoint lain, body selalu dibaca sebagai JSON tanpa memeriksa Content-Type.
+// fields memetakan nama field JSON ke pointer dari field pointer di struct patch, misalnya "price": &patch.Price,
+// sehingga field yang tidak dikirim tetap nil dan tidak diubah. Field opsional dibungkus dengan nullable sehingga
+// null mengosongkannya; null pada field wajib ditolak, begitu juga field yang tidak dikenal atau bertipe salah.
+// Semua pelanggaran dikembalikan sekaligus sebagai *models.ValidationError.
+func decodeMergePatch(r *http.Request, fields map[string]interface{}) error {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil || raw == nil {
+		return errInvalidBody
+	}
+
+	invalid := make(map[string]string)
+	for name, value := range raw {
+		target, ok := fields[name]
+		optional, isNullable := target.(nullableField)
+		if isNullable {
+			target = optional.target
+		}
+		switch {
+		case !ok:
+			invalid[name] = "is not a patchable field"
+		case bytes.Equal(bytes.TrimSpace(value), []byte("null")):
+			if !isNullable {
+				invalid[name] = "must not be null"
+				continue
+			}
+			// target adalah pointer ke field pointer: isi field dengan pointer ke nilai nol agar tetap dianggap dikirim.
+			field := reflect.ValueOf(target).Elem()
+			field.Set(reflect.New(field.Type().Elem()))
+		default:
+			if err := json.Unmarshal(value, target); err != nil {
+				invalid[name] = "has an invalid type"
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		return &models.ValidationError{Fields: invalid}
+	}
+	return nil
+}
+
+// decodeReplacement membaca body JSON PUT ke v seperti json.Decoder, tetapi menolak field stock dengan
+// *models.ValidationError karena stok hanya bisa diubah lewat penyesuaian stok. Body yang bukan JSON object valid
+// mengembalikan errInvalidBody; keduanya dikirim dengan writePatchError.
+func decodeReplacement(r *http.Request, v interface{}) error {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil || raw == nil {
+		return errInvalidBody
+	}
+	if _, ok := raw["stock"]; ok {
+		return models.NewValidationError("stock", "is read-only; use stock adjustments")
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return errInvalidBody
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return errInvalidBody
+	}
+	return nil
+}
+
+// writePatchError mengirim response error untuk kegagalan decodeMergePatch.
+func writePatchError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, errInvalidBody):
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
+	default:
+		writeError(w, r, err)
+	}
+}
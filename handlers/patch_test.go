@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http/httptest"
+	"strings"
+	"task-session-1/models"
+	"testing"
+)
+
+func TestDecodeMergePatch(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		want       models.ProductPatch
+		wantFields map[string]string
+	}{
+		{
+			name: "only sent fields are set",
+			body: `{"price": 12000}`,
+			want: models.ProductPatch{Price: intPtr(12000)},
+		},
+		{
+			name: "null clears optional fields",
+			body: `{"sku": null, "barcode": null, "reorder_level": null}`,
+			want: models.ProductPatch{SKU: stringPtr(""), Barcode: stringPtr(""), ReorderLevel: intPtr(0)},
+		},
+		{
+			name:       "null on required field is rejected",
+			body:       `{"name": null, "sku": null}`,
+			wantFields: map[string]string{"name": "must not be null"},
+		},
+		{
+			name: "unknown field and wrong type are rejected together",
+			body: `{"stock": 5, "price": "12000"}`,
+			wantFields: map[string]string{
+				"stock": "is not a patchable field",
+				"price": "has an invalid type",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch models.ProductPatch
+			r := httptest.NewRequest("PATCH", "/api/product/1", strings.NewReader(tt.body))
+			err := decodeMergePatch(r, map[string]interface{}{
+				"name":          &patch.Name,
+				"price":         &patch.Price,
+				"sku":           nullable(&patch.SKU),
+				"barcode":       nullable(&patch.Barcode),
+				"reorder_level": nullable(&patch.ReorderLevel),
+			})
+
+			if tt.wantFields != nil {
+				var validationErr *models.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("error = %v, want ValidationError", err)
+				}
+				if !maps.Equal(validationErr.Fields, tt.wantFields) {
+					t.Errorf("fields = %v, want %v", validationErr.Fields, tt.wantFields)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got, want := patchString(patch), patchString(tt.want); got != want {
+				t.Errorf("patch = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeMergePatchInvalidBody(t *testing.T) {
+	for _, body := range []string{``, `null`, `[1]`, `{"price":`} {
+		r := httptest.NewRequest("PATCH", "/api/product/1", strings.NewReader(body))
+		var patch models.ProductPatch
+		if err := decodeMergePatch(r, map[string]interface{}{"price": &patch.Price}); !errors.Is(err, errInvalidBody) {
+			t.Errorf("body %q: error = %v, want errInvalidBody", body, err)
+		}
+	}
+}
+
+func TestDecodeReplacement(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		want       string
+		wantFields map[string]string
+		wantErr    error
+	}{
+		{name: "body without stock is decoded", body: `{"name": "Tea", "price": 5, "category_id": 1}`, want: "Tea"},
+		{
+			name:       "stock is rejected",
+			body:       `{"name": "Tea", "price": 5, "category_id": 1, "stock": 999}`,
+			wantFields: map[string]string{"stock": "is read-only; use stock adjustments"},
+		},
+		{name: "invalid json", body: `{"name":`, wantErr: errInvalidBody},
+		{name: "wrong type", body: `{"name": "Tea", "price": "5"}`, wantErr: errInvalidBody},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var product models.Product
+			r := httptest.NewRequest("PUT", "/api/product/1", strings.NewReader(tt.body))
+			err := decodeReplacement(r, &product)
+
+			switch {
+			case tt.wantFields != nil:
+				var validationErr *models.ValidationError
+				if !errors.As(err, &validationErr) || !maps.Equal(validationErr.Fields, tt.wantFields) {
+					t.Fatalf("error = %v, want fields %v", err, tt.wantFields)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case product.Name != tt.want:
+				t.Errorf("name = %q, want %q", product.Name, tt.want)
+			}
+		})
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func stringPtr(v string) *string { return &v }
+
+// patchString menampilkan patch sebagai JSON: field nil menjadi null, sehingga field yang tidak dikirim bisa
+// dibedakan dari field yang dikosongkan.
+func patchString(p models.ProductPatch) string {
+	data, _ := json.Marshal(p)
+	return string(data)
+}
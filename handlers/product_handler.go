@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
 	"net/http"
 	"strconv"
-	"strings"
 	"task-session-1/models"
 	"task-session-1/services"
 )
@@ -21,133 +24,350 @@ func NewProductHandler(service *services.ProductService) *ProductHandler {
 	return &ProductHandler{service: service}
 }
 
-// HandleProduct menangani request ke /api/product.
-// Berdasarkan metode HTTP (GET untuk GetAll, POST untuk Create).
-// Jika metode tidak didukung, kembalikan error 405 Method Not Allowed.
-func (h *ProductHandler) HandleProduct(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.GetAll(w, r)
-	case http.MethodPost:
-		h.Create(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// GetAll menangani GET /api/product untuk mengambil daftar produk.
+// Filter, pengurutan, dan paginasi dibaca dari query string (lihat parseProductFilter).
+// Dengan ?q=, produk dicari di nama, SKU, dan nama kategori lalu diurutkan berdasarkan relevansi (field "score").
+// Produk yang sudah dihapus hanya ikut ditampilkan dengan ?include_deleted=true dari admin.
+// Response berupa Page: {"items": [...], "next_cursor": "...", "total": n}.
+// Jika parameter tidak valid, kirim response 422 dengan pesan per parameter.
+func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseProductFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !checkIncludeDeleted(w, r, filter.IncludeDeleted) {
+		return
+	}
+
+	page, err := h.service.GetAll(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
+
+	writeJSON(w, http.StatusOK, page)
 }
 
-func (h *ProductHandler) HandleProductByID(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.GetByID(w, r)
-	case http.MethodPut:
-		h.Update(w, r)
-	case http.MethodDelete:
-		h.Delete(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+// Suggest menangani GET /api/product/suggest?q= untuk autocomplete kotak pencarian kasir.
+// Response berupa {"items": [...]} berisi maksimal limit (default 10, maksimal 20) produk aktif
+// yang paling relevan, dengan field ringkas: id, name, sku, price, stock, dan nama category.
+func (h *ProductHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseSuggestFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
 	}
+
+	suggestions, err := h.service.Suggest(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": suggestions})
 }
 
-// GetAll menangani GET /api/product untuk mengambil semua produk.
-// Memanggil service.GetAll(), lalu encode hasil ke JSON dan kirim sebagai response.
-// Jika ada error, kembalikan status 500 Internal Server Error.
-func (h *ProductHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	name := r.URL.Query().Get("name")
-	product, err := h.service.GetAll(name)
+// GetByCategory menangani GET /api/category/{id}/products untuk mengambil produk dalam satu kategori.
+// Menerima filter, pengurutan, paginasi, dan include yang sama dengan GetAll;
+// parameter category_id di query string diabaikan karena kategori diambil dari path.
+func (h *ProductHandler) GetByCategory(w http.ResponseWriter, r *http.Request) {
+	categoryID, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid category id", nil)
+		return
+	}
+
+	filter, err := parseProductFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !checkIncludeDeleted(w, r, filter.IncludeDeleted) {
+		return
+	}
+
+	page, err := h.service.GetByCategory(r.Context(), categoryID, filter)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(product)
+	writeJSON(w, http.StatusOK, page)
 }
 
 // Create menangani POST /api/product untuk membuat produk baru.
 // Decode JSON dari request body ke struct Product.
 // Panggil service.Create(), lalu encode hasil ke JSON dan kirim sebagai response dengan status 201 Created.
-// Jika ada error, kembalikan status 400 Bad Request atau 500 Internal Server Error.
+// Jika body tidak valid, kembalikan status 400 Bad Request; kategori yang tidak ada menghasilkan 422.
 func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var product models.Product
 	err := json.NewDecoder(r.Body).Decode(&product)
 	if err != nil {
-		http.Error(w, "Invalid request body!", http.StatusBadRequest)
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
 		return
 	}
 
-	err = h.service.Create(&product)
+	err = h.service.Create(r.Context(), &product)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(product)
+	writeJSON(w, http.StatusCreated, product)
 }
 
+// GetByID menangani GET /api/product/{id} untuk mengambil produk berdasarkan ID.
+// Dengan ?include=category, kategori produk ikut dikirim di field "category".
+// Produk yang sudah dihapus menghasilkan 404, kecuali admin mengirim ?include_deleted=true.
 func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/product/")
-	id, err := strconv.Atoi(idStr)
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
+		return
+	}
+
+	opts, err := parseProductOptions(r.URL.Query())
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+	if !checkIncludeDeleted(w, r, opts.IncludeDeleted) {
 		return
 	}
 
-	category, err := h.service.GetByID(id)
+	product, err := h.service.GetByID(r.Context(), id, opts)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusNotFound)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(category)
+	writeJSON(w, http.StatusOK, product)
 }
 
-func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
-	// Ekstrak ID dari URL, decode JSON dari body.
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/product/")
-	id, err := strconv.Atoi(idStr)
+// GetByBarcode menangani GET /api/product/barcode/{code} untuk lookup produk dari scanner kasir.
+// Mendukung ?include=category seperti GetByID. Barcode yang tidak valid menghasilkan 422,
+// dan barcode yang tidak terdaftar pada produk aktif menghasilkan 404.
+func (h *ProductHandler) GetByBarcode(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseProductOptions(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
+	product, err := h.service.GetByBarcode(r.Context(), r.PathValue("code"), opts)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, product)
+}
+
+// Update menangani PUT /api/product/{id} untuk menimpa semua field produk. Field stock ditolak dengan 422
+// karena stok hanya bisa diubah lewat POST /api/product/{id}/stock-adjustments; response berisi stok saat ini.
+func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
+	// Ekstrak ID dari URL, decode JSON dari body.
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
 		return
 	}
 
 	var product models.Product
-	err = json.NewDecoder(r.Body).Decode(&product)
+	err := decodeReplacement(r, &product)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writePatchError(w, r, err)
 		return
 	}
 
 	product.ID = id
-	err = h.service.Update(&product)
+	err = h.service.Update(r.Context(), &product)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(product)
+	writeJSON(w, http.StatusOK, product)
 }
 
-func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
-	idStr := strings.TrimPrefix(r.URL.Path, "/api/product/")
-	id, err := strconv.Atoi(idStr)
+// Patch menangani PATCH /api/product/{id} dengan semantik JSON Merge Patch.
+// Hanya field yang dikirim (name, price, category_id, sku, barcode, reorder_level, reorder_qty) yang diubah,
+// lalu produk setelah diperbarui dikirim sebagai response. Nilai null pada field opsional (sku, barcode,
+// reorder_level, reorder_qty) mengosongkannya. Field stock ditolak dengan 422 karena stok hanya
+// bisa diubah lewat POST /api/product/{id}/stock-adjustments.
+func (h *ProductHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
+		return
+	}
+
+	var patch models.ProductPatch
+	err := decodeMergePatch(r, map[string]interface{}{
+		"name":          &patch.Name,
+		"price":         &patch.Price,
+		"category_id":   &patch.CategoryID,
+		"sku":           nullable(&patch.SKU),
+		"barcode":       nullable(&patch.Barcode),
+		"reorder_level": nullable(&patch.ReorderLevel),
+		"reorder_qty":   nullable(&patch.ReorderQty),
+	})
+	if err != nil {
+		writePatchError(w, r, err)
+		return
+	}
+
+	product, err := h.service.Patch(r.Context(), id, patch)
 	if err != nil {
-		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, product)
+}
+
+// Delete menangani DELETE /api/product/{id} untuk menghapus produk secara soft delete.
+// Produk tetap tersimpan sehingga laporan transaksi lama masih menampilkan namanya.
+func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
 		return
 	}
 
-	err = h.service.Delete(id)
+	err := h.service.Delete(r.Context(), id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{
+	writeJSON(w, http.StatusOK, map[string]string{
 		"message": "product deleted successfully",
 	})
 }
+
+// Restore menangani POST /api/product/{id}/restore untuk mengembalikan produk yang sudah dihapus.
+// Jika kategori produk masih terhapus, kembalikan status 409 Conflict.
+func (h *ProductHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
+		return
+	}
+
+	product, err := h.service.Restore(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, product)
+}
+
+// maxImportBytes adalah batas ukuran body untuk import CSV.
+const maxImportBytes = 10 << 20
+
+// Import menangani POST /api/product/import untuk membuat atau memperbarui banyak produk dari file CSV.
+// File dikirim sebagai body (Content-Type: text/csv) atau sebagai field "file" pada multipart/form-data.
+// Dengan ?dry_run=true, hasil import dihitung tanpa menyimpan data.
+// Jika ada baris yang tidak valid, kirim response 422 berisi kesalahan per baris dan tidak ada produk yang disimpan.
+func (h *ProductHandler) Import(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseDryRun(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	file, ok := uploadedFile(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.Import(r.Context(), file, dryRun)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// uploadedFile mengambil file CSV yang diunggah, dengan batas ukuran maxImportBytes. File dikirim sebagai body
+// (Content-Type: text/csv) atau sebagai field "file" pada multipart/form-data.
+// Mengembalikan false jika response error sudah dikirim; jika tidak, pemanggil harus menutup file.
+func uploadedFile(w http.ResponseWriter, r *http.Request) (io.ReadCloser, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "multipart/form-data" {
+		return r.Body, true
+	}
+
+	part, _, err := r.FormFile("file")
+	if err != nil {
+		var tooLargeErr *http.MaxBytesError
+		if errors.As(err, &tooLargeErr) {
+			writeError(w, r, err)
+			return nil, false
+		}
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, `multipart body must contain a "file" field`, nil)
+		return nil, false
+	}
+	return part, true
+}
+
+// Export menangani GET /api/product/export?format=csv untuk mengunduh katalog produk sebagai CSV.
+// Menerima filter dan pengurutan yang sama dengan GetAll; paginasi diabaikan karena semua produk yang cocok dikirim.
+// Response dikirim bertahap per halaman, sehingga error di tengah export hanya bisa dicatat di log.
+func (h *ProductHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if format := r.URL.Query().Get("format"); format != "" && format != "csv" {
+		writeError(w, r, models.NewValidationError("format", "unsupported value "+strconv.Quote(format)+`, only "csv" is supported`))
+		return
+	}
+
+	filter, err := parseProductFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if !checkIncludeDeleted(w, r, filter.IncludeDeleted) {
+		return
+	}
+
+	out := &streamWriter{w: w, rc: http.NewResponseController(w), header: func(h http.Header) {
+		h.Set("Content-Type", "text/csv; charset=utf-8")
+		h.Set("Content-Disposition", `attachment; filename="products.csv"`)
+	}}
+	if err := h.service.Export(r.Context(), out, filter); err != nil {
+		if !out.started {
+			writeError(w, r, err)
+			return
+		}
+		slog.ErrorContext(r.Context(), "export interrupted", "path", r.URL.Path, "request_id", requestID(r), "error", err)
+	}
+}
+
+// streamWriter meneruskan data ke client dan langsung mengirimnya (flush) setelah setiap Write.
+// Header response baru ditulis saat Write pertama, sehingga error sebelum itu masih bisa dikirim sebagai JSON.
+type streamWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	header  func(http.Header)
+	started bool
+}
+
+func (s *streamWriter) Write(b []byte) (int, error) {
+	if !s.started {
+		s.started = true
+		s.header(s.w.Header())
+		s.w.WriteHeader(http.StatusOK)
+	}
+	n, err := s.w.Write(b)
+	if err != nil {
+		return n, err
+	}
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return n, err
+	}
+	return n, nil
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"task-session-1/models"
+	"task-session-1/services"
+)
+
+// PurchaseOrderHandler adalah struct yang menangani request HTTP untuk purchase order.
+type PurchaseOrderHandler struct {
+	service *services.PurchaseOrderService
+}
+
+// NewPurchaseOrderHandler adalah konstruktor untuk membuat instance PurchaseOrderHandler.
+func NewPurchaseOrderHandler(service *services.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{service: service}
+}
+
+// GetAll menangani GET /api/purchase-order untuk mengambil daftar purchase order beserta barisnya.
+// Filter status (termasuk open untuk ordered dan partially_received) dan supplier_id, serta paginasi
+// dengan sort id atau -id, dibaca dari query string.
+func (h *PurchaseOrderHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := parsePurchaseOrderFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := h.service.GetAll(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// Create menangani POST /api/purchase-order untuk membuat purchase order berisi supplier_id, note,
+// dan items (product_id, variant_id, quantity, unit_cost). Response 201 berisi purchase order berstatus ordered.
+func (h *PurchaseOrderHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var po models.PurchaseOrder
+	if err := json.NewDecoder(r.Body).Decode(&po); err != nil {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := h.service.Create(r.Context(), &po); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, po)
+}
+
+// GetByID menangani GET /api/purchase-order/{id} untuk mengambil purchase order beserta barisnya.
+func (h *PurchaseOrderHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid purchase order id", nil)
+		return
+	}
+
+	po, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, po)
+}
+
+// Receive menangani POST /api/purchase-order/{id}/receive untuk menerima barang dan menambah stok.
+// Body {"items": [{"item_id": 1, "quantity": 5}]} menerima sebagian barang; body kosong atau tanpa items
+// menerima semua sisa barang. Response berisi purchase order setelah diperbarui. Purchase order yang sudah
+// received atau cancelled ditolak dengan 409.
+func (h *PurchaseOrderHandler) Receive(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid purchase order id", nil)
+		return
+	}
+
+	var receipt models.PurchaseOrderReceipt
+	if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil && !errors.Is(err, io.EOF) {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
+		return
+	}
+
+	po, err := h.service.Receive(r.Context(), id, receipt)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, po)
+}
+
+// Cancel menangani POST /api/purchase-order/{id}/cancel untuk membatalkan purchase order yang masih open.
+func (h *PurchaseOrderHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid purchase order id", nil)
+		return
+	}
+
+	po, err := h.service.Cancel(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, po)
+}
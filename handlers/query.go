@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"task-session-1/models"
+)
+
+// queryParser membaca parameter query string dan mengumpulkan semua parameter yang tidak valid,
+// sehingga client menerima semua kesalahan sekaligus dalam satu response 422.
+type queryParser struct {
+	values url.Values
+	fields map[string]string
+}
+
+func newQueryParser(values url.Values) *queryParser {
+	return &queryParser{values: values, fields: make(map[string]string)}
+}
+
+// int membaca parameter bilangan bulat, atau mengembalikan def jika parameter tidak diisi.
+func (p *queryParser) int(name string, def int) int {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return def
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		p.fields[name] = "must be an integer"
+		return def
+	}
+	return n
+}
+
+// optionalInt membaca parameter bilangan bulat, atau nil jika parameter tidak diisi.
+func (p *queryParser) optionalInt(name string) *int {
+	if p.values.Get(name) == "" {
+		return nil
+	}
+	n := p.int(name, 0)
+	return &n
+}
+
+// optionalBool membaca parameter boolean ("true", "false", "1", "0"), atau nil jika parameter tidak diisi.
+func (p *queryParser) optionalBool(name string) *bool {
+	raw := p.values.Get(name)
+	if raw == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		p.fields[name] = "must be true or false"
+		return nil
+	}
+	return &b
+}
+
+// listOptions membaca parameter paginasi dan pengurutan: limit, offset, cursor, dan sort.
+func (p *queryParser) listOptions() models.ListOptions {
+	opts := models.ListOptions{
+		Limit:  p.int("limit", models.DefaultPageLimit),
+		Offset: p.int("offset", 0),
+	}
+	opts.SetSort(p.values.Get("sort"))
+
+	if token := p.values.Get("cursor"); token != "" {
+		cursor, err := models.DecodeCursor(token)
+		if err != nil {
+			p.fields["cursor"] = "is invalid"
+		} else {
+			opts.Cursor = cursor
+		}
+	}
+	return opts
+}
+
+// productOptions membaca parameter include (dipisah koma) untuk data tambahan pada produk,
+// serta include_deleted. Nilai include yang didukung: "category" dan "variants".
+func (p *queryParser) productOptions() models.ProductOptions {
+	opts := models.ProductOptions{IncludeDeleted: p.includeDeleted()}
+	raw := p.values.Get("include")
+	if raw == "" {
+		return opts
+	}
+
+	for _, include := range strings.Split(raw, ",") {
+		switch strings.TrimSpace(include) {
+		case "category":
+			opts.IncludeCategory = true
+		case "variants":
+			opts.IncludeVariants = true
+		default:
+			p.fields["include"] = "unsupported value " + strconv.Quote(include)
+		}
+	}
+	return opts
+}
+
+// includeDeleted membaca parameter include_deleted untuk menampilkan data yang sudah dihapus.
+// Hanya admin yang boleh memakainya; pemeriksaan dilakukan di handler dengan checkIncludeDeleted.
+func (p *queryParser) includeDeleted() bool {
+	include := p.optionalBool("include_deleted")
+	return include != nil && *include
+}
+
+// err mengembalikan *models.ValidationError jika ada parameter yang tidak valid.
+func (p *queryParser) err() error {
+	if len(p.fields) == 0 {
+		return nil
+	}
+	return &models.ValidationError{Fields: p.fields}
+}
+
+// parseProductFilter membaca filter daftar produk dari query string:
+// q, name, category_id, min_price, max_price, in_stock, include, include_deleted, serta parameter paginasi dan pengurutan.
+// Pencarian dengan q tanpa sort diurutkan berdasarkan relevansi tertinggi.
+func parseProductFilter(values url.Values) (models.ProductFilter, error) {
+	p := newQueryParser(values)
+	filter := models.ProductFilter{
+		ListOptions:    p.listOptions(),
+		ProductOptions: p.productOptions(),
+		Query:          strings.TrimSpace(values.Get("q")),
+		Name:           values.Get("name"),
+		CategoryID:     p.int("category_id", 0),
+		MinPrice:       p.optionalInt("min_price"),
+		MaxPrice:       p.optionalInt("max_price"),
+		InStock:        p.optionalBool("in_stock"),
+	}
+	if filter.Query != "" && values.Get("sort") == "" {
+		filter.SetSort("-relevance")
+	}
+	return filter, p.err()
+}
+
+// parseCategoryFilter membaca filter daftar kategori dari query string:
+// name, include_deleted, serta parameter paginasi dan pengurutan.
+func parseCategoryFilter(values url.Values) (models.CategoryFilter, error) {
+	p := newQueryParser(values)
+	filter := models.CategoryFilter{
+		ListOptions:     p.listOptions(),
+		CategoryOptions: p.categoryOptions(),
+		Name:            values.Get("name"),
+	}
+	return filter, p.err()
+}
+
+// categoryOptions membaca parameter include_deleted untuk kategori.
+func (p *queryParser) categoryOptions() models.CategoryOptions {
+	return models.CategoryOptions{IncludeDeleted: p.includeDeleted()}
+}
+
+// parseProductOptions membaca parameter include dan include_deleted untuk endpoint detail produk.
+func parseProductOptions(values url.Values) (models.ProductOptions, error) {
+	p := newQueryParser(values)
+	opts := p.productOptions()
+	return opts, p.err()
+}
+
+// parseCategoryOptions membaca parameter include_deleted untuk endpoint detail kategori.
+func parseCategoryOptions(values url.Values) (models.CategoryOptions, error) {
+	p := newQueryParser(values)
+	opts := p.categoryOptions()
+	return opts, p.err()
+}
+
+// parseCategoryDeletePolicy membaca mode penghapusan kategori dari query string:
+// mode (restrict, reassign, cascade; default restrict) dan target_category_id untuk mode reassign.
+func parseCategoryDeletePolicy(values url.Values) (models.CategoryDeletePolicy, error) {
+	p := newQueryParser(values)
+	policy := models.CategoryDeletePolicy{
+		Mode:             models.CategoryDeleteMode(values.Get("mode")),
+		TargetCategoryID: p.int("target_category_id", 0),
+	}
+	if policy.Mode == "" {
+		policy.Mode = models.CategoryDeleteRestrict
+	}
+	return policy, p.err()
+}
+
+// parseStockMovementFilter membaca filter riwayat stok dari query string:
+// variant_id, reason, serta parameter paginasi dan pengurutan. Tanpa sort, pergerakan terbaru ditampilkan lebih dulu.
+func parseStockMovementFilter(values url.Values) (models.StockMovementFilter, error) {
+	p := newQueryParser(values)
+	filter := models.StockMovementFilter{
+		ListOptions: p.listOptions(),
+		VariantID:   p.optionalInt("variant_id"),
+		Reason:      models.StockReason(values.Get("reason")),
+	}
+	if values.Get("sort") == "" {
+		filter.SetSort("-id")
+	}
+	return filter, p.err()
+}
+
+// parseLowStockFilter membaca filter daftar stok menipis dari query string: category_id.
+func parseLowStockFilter(values url.Values) (models.LowStockFilter, error) {
+	p := newQueryParser(values)
+	filter := models.LowStockFilter{
+		CategoryID: p.int("category_id", 0),
+	}
+	return filter, p.err()
+}
+
+// parseSupplierFilter membaca filter daftar supplier dari query string: name, serta parameter paginasi dan pengurutan.
+func parseSupplierFilter(values url.Values) (models.SupplierFilter, error) {
+	p := newQueryParser(values)
+	filter := models.SupplierFilter{
+		ListOptions: p.listOptions(),
+		Name:        values.Get("name"),
+	}
+	return filter, p.err()
+}
+
+// parsePurchaseOrderFilter membaca filter daftar purchase order dari query string: status, supplier_id,
+// serta parameter paginasi dan pengurutan.
+func parsePurchaseOrderFilter(values url.Values) (models.PurchaseOrderFilter, error) {
+	p := newQueryParser(values)
+	filter := models.PurchaseOrderFilter{
+		ListOptions: p.listOptions(),
+		Status:      models.PurchaseOrderStatus(values.Get("status")),
+		SupplierID:  p.int("supplier_id", 0),
+	}
+	return filter, p.err()
+}
+
+// parseStockCountFilter membaca filter daftar sesi stock opname dari query string: status, serta parameter
+// paginasi dan pengurutan.
+func parseStockCountFilter(values url.Values) (models.StockCountFilter, error) {
+	p := newQueryParser(values)
+	filter := models.StockCountFilter{
+		ListOptions: p.listOptions(),
+		Status:      models.StockCountStatus(values.Get("status")),
+	}
+	return filter, p.err()
+}
+
+// parseSuggestFilter membaca parameter autocomplete produk dari query string: q dan limit.
+func parseSuggestFilter(values url.Values) (models.SuggestFilter, error) {
+	p := newQueryParser(values)
+	filter := models.SuggestFilter{
+		Query: strings.TrimSpace(values.Get("q")),
+		Limit: p.int("limit", models.DefaultSuggestLimit),
+	}
+	return filter, p.err()
+}
+
+// parseDryRun membaca parameter dry_run (default false) untuk endpoint yang bisa dijalankan tanpa menyimpan data.
+func parseDryRun(values url.Values) (bool, error) {
+	p := newQueryParser(values)
+	dryRun := p.optionalBool("dry_run")
+	return dryRun != nil && *dryRun, p.err()
+}
+
+// checkIncludeDeleted menolak include_deleted=true dari request yang bukan admin dengan response 401.
+// Mengembalikan false jika response error sudah dikirim.
+func checkIncludeDeleted(w http.ResponseWriter, r *http.Request, includeDeleted bool) bool {
+	if includeDeleted && !IsAdmin(r.Context()) {
+		writeAdminRequired(w, r)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"task-session-1/models"
+)
+
+// Kode error yang dikirim di field "code" pada response error.
+const (
+	codeBadRequest        = "bad_request"
+	codeUnauthorized      = "unauthorized"
+	codeNotFound          = "not_found"
+	codeMethodNotAllowed  = "method_not_allowed"
+	codeValidation        = "validation_failed"
+	codeConflict          = "conflict"
+	codePayloadTooLarge   = "payload_too_large"
+	codeInsufficientStock = "insufficient_stock"
+	codeTimeout           = "timeout"
+	codeInternal          = "internal_error"
+)
+
+// ErrorResponse adalah format JSON untuk semua response error.
+// Details berisi informasi tambahan, misalnya pesan per field untuk error validasi.
+type ErrorResponse struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// writeJSON mengirim v sebagai JSON dengan status code tertentu.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError memetakan error dari service atau repository ke status HTTP dan response JSON.
+// Error yang tidak dikenal dianggap 500 dan pesannya tidak dikirim ke client, hanya dicatat di log.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		validationErr *models.ValidationError
+		importErr     *models.ImportError
+		conflictErr   *models.ConflictError
+		stockErr      *models.InsufficientStockError
+		tooLargeErr   *http.MaxBytesError
+	)
+
+	switch {
+	case errors.As(err, &validationErr):
+		writeErrorResponse(w, r, http.StatusUnprocessableEntity, codeValidation, "request validation failed", validationErr.Fields)
+	case errors.As(err, &importErr):
+		writeErrorResponse(w, r, http.StatusUnprocessableEntity, codeValidation, "import failed; no rows were saved",
+			map[string]interface{}{"rows": importErr.Rows})
+	case errors.Is(err, models.ErrValidation):
+		writeErrorResponse(w, r, http.StatusUnprocessableEntity, codeValidation, err.Error(), nil)
+	case errors.Is(err, models.ErrNotFound):
+		writeErrorResponse(w, r, http.StatusNotFound, codeNotFound, err.Error(), nil)
+	case errors.As(err, &stockErr):
+		details := map[string]int{
+			"product_id": stockErr.ProductID,
+			"requested":  stockErr.Requested,
+			"available":  stockErr.Available,
+		}
+		if stockErr.VariantID != 0 {
+			details["variant_id"] = stockErr.VariantID
+		}
+		writeErrorResponse(w, r, http.StatusConflict, codeInsufficientStock, err.Error(), details)
+	case errors.As(err, &conflictErr):
+		var details interface{}
+		if conflictErr.Details != nil {
+			details = conflictErr.Details
+		}
+		writeErrorResponse(w, r, http.StatusConflict, codeConflict, conflictErr.Message, details)
+	case errors.Is(err, models.ErrConflict):
+		writeErrorResponse(w, r, http.StatusConflict, codeConflict, err.Error(), nil)
+	case errors.As(err, &tooLargeErr):
+		writeErrorResponse(w, r, http.StatusRequestEntityTooLarge, codePayloadTooLarge,
+			fmt.Sprintf("request body must not exceed %d bytes", tooLargeErr.Limit), nil)
+	case errors.Is(err, context.DeadlineExceeded):
+		writeErrorResponse(w, r, http.StatusServiceUnavailable, codeTimeout, "request timed out", nil)
+	default:
+		slog.ErrorContext(r.Context(), "internal error", "method", r.Method, "path", r.URL.Path, "request_id", requestID(r), "error", err)
+		writeErrorResponse(w, r, http.StatusInternalServerError, codeInternal, "internal server error", nil)
+	}
+}
+
+// writeErrorResponse mengirim response error JSON dengan status, kode, dan pesan yang ditentukan.
+// Dipakai langsung untuk error di level HTTP seperti body tidak valid atau ID di URL tidak valid.
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, status int, code, message string, details interface{}) {
+	writeJSON(w, status, ErrorResponse{
+		Code:      code,
+		Message:   message,
+		Details:   details,
+		RequestID: requestID(r),
+	})
+}
+
+// writeMethodNotAllowed mengirim response 405 untuk metode HTTP yang tidak didukung.
+func writeMethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeErrorResponse(w, r, http.StatusMethodNotAllowed, codeMethodNotAllowed, "method not allowed", nil)
+}
+
+// requestID mengambil ID request dari context yang diisi middleware RequestID agar error bisa dilacak di log.
+func requestID(r *http.Request) string {
+	return RequestIDFromContext(r.Context())
+}
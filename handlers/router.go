@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// RouterConfig berisi handler dan pengaturan yang dibutuhkan NewRouter untuk mendaftarkan route.
+// AdminToken dipakai oleh RequireAdmin untuk endpoint admin dan oleh IdentifyAdmin untuk opsi khusus admin.
+// FeatureCheckout dan FeatureReports menentukan apakah endpoint checkout dan laporan didaftarkan.
+type RouterConfig struct {
+	Category      *CategoryHandler
+	Product       *ProductHandler
+	Variant       *VariantHandler
+	Stock         *StockHandler
+	Supplier      *SupplierHandler
+	PurchaseOrder *PurchaseOrderHandler
+	StockCount    *StockCountHandler
+	Transaction   *TransactionHandler
+	Health        *HealthHandler
+
+	AdminToken      string
+	FeatureCheckout bool
+	FeatureReports  bool
+}
+
+// NewRouter membuat http.Handler dengan semua route aplikasi.
+// Route memakai pola method + path dari Go 1.22 (misalnya "GET /api/product/{id}"),
+// sehingga method yang salah menghasilkan 405 dan path yang tidak dikenal menghasilkan 404.
+// Semua request melewati middleware RequestID, Logging, Recovery, dan IdentifyAdmin dengan urutan tersebut.
+// Restore data yang sudah dihapus hanya untuk admin.
+func NewRouter(cfg RouterConfig) http.Handler {
+	mux := http.NewServeMux()
+	admin := RequireAdmin(cfg.AdminToken)
+
+	// Health check untuk load balancer dan orchestrator, serta diagnostik untuk admin.
+	mux.HandleFunc("GET /healthz", cfg.Health.Healthz)
+	mux.HandleFunc("GET /readyz", cfg.Health.Readyz)
+	mux.Handle("GET /debug/status", admin(http.HandlerFunc(cfg.Health.DebugStatus)))
+
+	// Kategori.
+	mux.HandleFunc("GET /api/category", cfg.Category.GetAll)
+	mux.HandleFunc("POST /api/category", cfg.Category.Create)
+	mux.HandleFunc("GET /api/category/{id}", cfg.Category.GetByID)
+	mux.HandleFunc("PUT /api/category/{id}", cfg.Category.Update)
+	mux.HandleFunc("PATCH /api/category/{id}", cfg.Category.Patch)
+	mux.HandleFunc("DELETE /api/category/{id}", cfg.Category.Delete)
+	mux.Handle("POST /api/category/{id}/restore", admin(http.HandlerFunc(cfg.Category.Restore)))
+	mux.HandleFunc("GET /api/category/{id}/products", cfg.Product.GetByCategory)
+
+	// Produk.
+	mux.HandleFunc("GET /api/product", cfg.Product.GetAll)
+	mux.HandleFunc("POST /api/product", cfg.Product.Create)
+	mux.HandleFunc("GET /api/product/{id}", cfg.Product.GetByID)
+	mux.HandleFunc("GET /api/product/barcode/{code}", cfg.Product.GetByBarcode)
+	mux.HandleFunc("GET /api/product/export", cfg.Product.Export)
+	mux.HandleFunc("GET /api/product/suggest", cfg.Product.Suggest)
+	mux.HandleFunc("POST /api/product/import", cfg.Product.Import)
+	mux.HandleFunc("PUT /api/product/{id}", cfg.Product.Update)
+	mux.HandleFunc("PATCH /api/product/{id}", cfg.Product.Patch)
+	mux.HandleFunc("DELETE /api/product/{id}", cfg.Product.Delete)
+	mux.Handle("POST /api/product/{id}/restore", admin(http.HandlerFunc(cfg.Product.Restore)))
+	mux.HandleFunc("POST /api/product/{id}/stock-adjustments", cfg.Stock.Adjust)
+
+	// Sub-resource produk untuk GET. Pola "GET /api/product/{id}/variants" akan bentrok dengan
+	// "GET /api/product/barcode/{code}" (keduanya cocok dengan /api/product/barcode/variants dan ServeMux panik),
+	// sehingga semua GET sub-resource didaftarkan lewat satu pola yang lebih umum dan dipilih dengan subroutes.
+	mux.HandleFunc("GET /api/product/{id}/{resource}", subroutes(map[string]http.HandlerFunc{
+		"variants":      cfg.Variant.List,
+		"stock-history": cfg.Stock.History,
+	}))
+
+	// Varian produk.
+	mux.HandleFunc("POST /api/product/{id}/variants", cfg.Variant.Create)
+	mux.HandleFunc("PUT /api/product/{id}/variants/{variant_id}", cfg.Variant.Update)
+	mux.HandleFunc("DELETE /api/product/{id}/variants/{variant_id}", cfg.Variant.Delete)
+
+	// Inventaris: produk dan varian yang perlu dipesan ulang.
+	mux.HandleFunc("GET /api/inventory/low-stock", cfg.Stock.LowStock)
+
+	// Supplier dan purchase order untuk pengadaan barang.
+	mux.HandleFunc("GET /api/supplier", cfg.Supplier.GetAll)
+	mux.HandleFunc("POST /api/supplier", cfg.Supplier.Create)
+	mux.HandleFunc("GET /api/supplier/{id}", cfg.Supplier.GetByID)
+	mux.HandleFunc("PUT /api/supplier/{id}", cfg.Supplier.Update)
+	mux.HandleFunc("GET /api/purchase-order", cfg.PurchaseOrder.GetAll)
+	mux.HandleFunc("POST /api/purchase-order", cfg.PurchaseOrder.Create)
+	mux.HandleFunc("GET /api/purchase-order/{id}", cfg.PurchaseOrder.GetByID)
+	mux.HandleFunc("POST /api/purchase-order/{id}/receive", cfg.PurchaseOrder.Receive)
+	mux.HandleFunc("POST /api/purchase-order/{id}/cancel", cfg.PurchaseOrder.Cancel)
+
+	// Stock opname: sesi penghitungan stok fisik dan posting selisihnya.
+	mux.HandleFunc("GET /api/stock-count", cfg.StockCount.GetAll)
+	mux.HandleFunc("POST /api/stock-count", cfg.StockCount.Create)
+	mux.HandleFunc("GET /api/stock-count/{id}", cfg.StockCount.GetByID)
+	mux.HandleFunc("GET /api/stock-count/{id}/variance", cfg.StockCount.Variance)
+	mux.HandleFunc("POST /api/stock-count/{id}/counts", cfg.StockCount.RecordCounts)
+	mux.HandleFunc("POST /api/stock-count/{id}/counts/import", cfg.StockCount.ImportCounts)
+	mux.HandleFunc("POST /api/stock-count/{id}/post", cfg.StockCount.Post)
+	mux.HandleFunc("POST /api/stock-count/{id}/cancel", cfg.StockCount.Cancel)
+
+	// Checkout, hanya jika FEATURE_CHECKOUT aktif.
+	if cfg.FeatureCheckout {
+		mux.HandleFunc("POST /api/checkout", cfg.Transaction.Checkout)
+	}
+
+	// Laporan, hanya jika FEATURE_REPORTS aktif.
+	if cfg.FeatureReports {
+		mux.HandleFunc("GET /api/report/hari-ini", cfg.Transaction.ReportHariIni)
+		mux.HandleFunc("GET /api/report", cfg.Transaction.Report)
+	}
+
+	return Chain(jsonFallback(mux), RequestID, Logging, Recovery, IdentifyAdmin(cfg.AdminToken))
+}
+
+// jsonFallback mengganti response 404 dan 405 bawaan ServeMux (berupa teks biasa)
+// dengan format error JSON yang sama seperti endpoint lain. Header Allow dari ServeMux tetap dikirim.
+func jsonFallback(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, pattern := mux.Handler(r)
+		if pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &discardRecorder{header: w.Header()}
+		handler.ServeHTTP(rec, r)
+
+		switch rec.status {
+		case http.StatusMethodNotAllowed:
+			writeMethodNotAllowed(w, r)
+		case http.StatusNotFound:
+			writeErrorResponse(w, r, http.StatusNotFound, codeNotFound, "route not found", nil)
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
+
+// subroutes memilih handler berdasarkan parameter path {resource}.
+// Resource yang tidak dikenal menghasilkan 404 dengan format yang sama seperti route yang tidak ada.
+func subroutes(routes map[string]http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handler, ok := routes[r.PathValue("resource")]
+		if !ok {
+			writeErrorResponse(w, r, http.StatusNotFound, codeNotFound, "route not found", nil)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// discardRecorder mencatat status code tanpa menulis body ke client.
+// Header dibagikan dengan ResponseWriter asli agar header seperti Allow tetap terkirim.
+type discardRecorder struct {
+	header http.Header
+	status int
+}
+
+func (rec *discardRecorder) Header() http.Header         { return rec.header }
+func (rec *discardRecorder) Write(b []byte) (int, error) { return len(b), nil }
+func (rec *discardRecorder) WriteHeader(status int)      { rec.status = status }
+
+// pathID mengambil parameter {id} dari path dan mengonversinya ke int positif.
+func pathID(r *http.Request) (int, bool) {
+	return pathInt(r, "id")
+}
+
+// pathInt mengambil parameter path bernama name dan mengonversinya ke int positif.
+func pathInt(r *http.Request, name string) (int, bool) {
+	id, err := strconv.Atoi(r.PathValue(name))
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"task-session-1/models"
+	"task-session-1/services"
+)
+
+// StockCountHandler adalah struct yang menangani request HTTP untuk sesi stock opname.
+type StockCountHandler struct {
+	service *services.StockCountService
+}
+
+// NewStockCountHandler adalah konstruktor untuk membuat instance StockCountHandler.
+func NewStockCountHandler(service *services.StockCountService) *StockCountHandler {
+	return &StockCountHandler{service: service}
+}
+
+// GetAll menangani GET /api/stock-count untuk mengambil daftar sesi stock opname tanpa barisnya.
+// Filter status serta paginasi dengan sort id atau -id dibaca dari query string.
+func (h *StockCountHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseStockCountFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := h.service.GetAll(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// Create menangani POST /api/stock-count untuk membuka sesi stock opname yang menyalin stok saat ini.
+// Body opsional {"category_id": 1, "note": "..."} membatasi sesi ke satu kategori; body kosong menghitung semua
+// produk. Response 201 berisi sesi beserta barisnya. Sesi baru ditolak dengan 409 selama masih ada sesi open.
+func (h *StockCountHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var count models.StockCount
+	if err := json.NewDecoder(r.Body).Decode(&count); err != nil && !errors.Is(err, io.EOF) {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := h.service.Create(r.Context(), &count); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, count)
+}
+
+// GetByID menangani GET /api/stock-count/{id} untuk mengambil sesi stock opname beserta barisnya dan ringkasannya.
+func (h *StockCountHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid stock count id", nil)
+		return
+	}
+
+	count, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, count)
+}
+
+// Variance menangani GET /api/stock-count/{id}/variance untuk mengambil laporan selisih: ringkasan sesi dan
+// baris yang hasil hitungnya berbeda dari stok sistem.
+func (h *StockCountHandler) Variance(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid stock count id", nil)
+		return
+	}
+
+	report, err := h.service.Variance(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, report)
+}
+
+// RecordCounts menangani POST /api/stock-count/{id}/counts untuk mencatat hasil hitung fisik, misalnya
+// {"items": [{"product_id": 1, "counted_quantity": 12}, {"sku": "KAOS-M", "counted_quantity": 3}]}.
+// Hasil hitung sebelumnya untuk baris yang sama ditimpa. Response berisi sesi setelah diperbarui.
+func (h *StockCountHandler) RecordCounts(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid stock count id", nil)
+		return
+	}
+
+	var entries models.StockCountEntries
+	if err := json.NewDecoder(r.Body).Decode(&entries); err != nil {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
+		return
+	}
+
+	count, err := h.service.RecordCounts(r.Context(), id, entries)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, count)
+}
+
+// ImportCounts menangani POST /api/stock-count/{id}/counts/import untuk mencatat hasil hitung fisik dari file CSV
+// dengan kolom counted_quantity dan sku atau product_id (serta variant_id). File dikirim seperti import produk.
+// Jika ada baris yang tidak valid, kirim response 422 berisi kesalahan per baris dan tidak ada hasil hitung yang disimpan.
+func (h *StockCountHandler) ImportCounts(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid stock count id", nil)
+		return
+	}
+
+	file, ok := uploadedFile(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	count, err := h.service.ImportCounts(r.Context(), id, file)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, count)
+}
+
+// Post menangani POST /api/stock-count/{id}/post untuk menerapkan selisih hasil hitung ke stok dengan alasan
+// stock_opname. Response berisi sesi yang sudah posted. Sesi yang sudah posted atau cancelled ditolak dengan 409,
+// dan selisih yang akan membuat stok negatif ditolak dengan 409 insufficient_stock.
+func (h *StockCountHandler) Post(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid stock count id", nil)
+		return
+	}
+
+	count, err := h.service.Post(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, count)
+}
+
+// Cancel menangani POST /api/stock-count/{id}/cancel untuk membatalkan sesi stock opname yang masih open.
+func (h *StockCountHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid stock count id", nil)
+		return
+	}
+
+	count, err := h.service.Cancel(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, count)
+}
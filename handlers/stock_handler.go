@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"task-session-1/models"
+	"task-session-1/services"
+)
+
+// StockHandler adalah struct yang menangani request HTTP untuk ledger dan penyesuaian stok produk.
+type StockHandler struct {
+	service *services.StockService
+}
+
+// NewStockHandler adalah konstruktor untuk membuat instance StockHandler.
+func NewStockHandler(service *services.StockService) *StockHandler {
+	return &StockHandler{service: service}
+}
+
+// History menangani GET /api/product/{id}/stock-history untuk mengambil riwayat perubahan stok produk
+// beserta variannya, terbaru lebih dulu. Bisa difilter dengan variant_id dan reason, dan memakai paginasi yang sama
+// seperti daftar produk (limit, offset, cursor, sort=id atau -id).
+func (h *StockHandler) History(w http.ResponseWriter, r *http.Request) {
+	productID, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
+		return
+	}
+
+	filter, err := parseStockMovementFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	filter.ProductID = productID
+
+	page, err := h.service.History(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// Adjust menangani POST /api/product/{id}/stock-adjustments untuk menambah atau mengurangi stok produk
+// (atau varian dengan variant_id) sebesar delta dengan alasan restock, damage, correction, atau theft.
+// Response 201 berisi pergerakan stok yang dicatat, termasuk stock_after. Stok yang akan menjadi negatif
+// ditolak dengan 409 insufficient_stock.
+func (h *StockHandler) Adjust(w http.ResponseWriter, r *http.Request) {
+	productID, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
+		return
+	}
+
+	var adj models.StockAdjustment
+	if err := json.NewDecoder(r.Body).Decode(&adj); err != nil {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
+		return
+	}
+
+	movement, err := h.service.Adjust(r.Context(), productID, adj)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, movement)
+}
+
+// LowStock menangani GET /api/inventory/low-stock untuk mengambil produk dan varian yang stoknya sudah
+// mencapai reorder_level produknya, beserta reorder_qty sebagai saran jumlah pemesanan ulang.
+// Bisa difilter dengan category_id.
+func (h *StockHandler) LowStock(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseLowStockFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	items, err := h.service.LowStock(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"task-session-1/models"
+	"task-session-1/services"
+)
+
+// SupplierHandler adalah struct yang menangani request HTTP untuk supplier.
+type SupplierHandler struct {
+	service *services.SupplierService
+}
+
+// NewSupplierHandler adalah konstruktor untuk membuat instance SupplierHandler.
+func NewSupplierHandler(service *services.SupplierService) *SupplierHandler {
+	return &SupplierHandler{service: service}
+}
+
+// GetAll menangani GET /api/supplier untuk mengambil daftar supplier.
+// Filter name, pengurutan (id atau name), dan paginasi dibaca dari query string seperti daftar kategori.
+func (h *SupplierHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseSupplierFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := h.service.GetAll(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+// Create menangani POST /api/supplier untuk membuat supplier baru dengan response 201 Created.
+func (h *SupplierHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var supplier models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := h.service.Create(r.Context(), &supplier); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, supplier)
+}
+
+// GetByID menangani GET /api/supplier/{id} untuk mengambil supplier berdasarkan ID.
+func (h *SupplierHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid supplier id", nil)
+		return
+	}
+
+	supplier, err := h.service.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, supplier)
+}
+
+// Update menangani PUT /api/supplier/{id} untuk memperbarui semua field supplier.
+func (h *SupplierHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid supplier id", nil)
+		return
+	}
+
+	var supplier models.Supplier
+	if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
+		return
+	}
+
+	supplier.ID = id
+	if err := h.service.Update(r.Context(), &supplier); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, supplier)
+}
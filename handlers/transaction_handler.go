@@ -8,73 +8,45 @@ import (
 	"time"
 )
 
+// TransactionHandler menangani request HTTP untuk checkout dan laporan.
+// location adalah zona waktu bisnis yang dipakai untuk menentukan "hari ini".
 type TransactionHandler struct {
-	service *services.TransactionService
+	service  *services.TransactionService
+	location *time.Location
 }
 
-func NewTransactionHandler(service *services.TransactionService) *TransactionHandler {
-	return &TransactionHandler{service: service}
-}
-
-// multiple item apa aja, quantity nya
-func (h *TransactionHandler) HandleCheckout(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodPost:
-		h.Checkout(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
+func NewTransactionHandler(service *services.TransactionService, location *time.Location) *TransactionHandler {
+	return &TransactionHandler{service: service, location: location}
 }
 
+// Checkout menangani POST /api/checkout: multiple item apa aja, quantity nya.
 func (h *TransactionHandler) Checkout(w http.ResponseWriter, r *http.Request) {
 	var req models.CheckoutRequest
 	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
 		return
 	}
 
-	transaction, err := h.service.Checkout(req.Items, true)
+	transaction, err := h.service.Checkout(r.Context(), req.Items, true)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(transaction)
-}
-
-func (h *TransactionHandler) HandleReportHariIni(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.ReportHariIni(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
+	writeJSON(w, http.StatusOK, transaction)
 }
 
 func (h *TransactionHandler) ReportHariIni(w http.ResponseWriter, r *http.Request) {
-	now := time.Now()
-	startDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	endDate := startDate.AddDate(0, 0, 1).Add(-time.Second)
+	today := time.Now().In(h.location)
 
-	report, err := h.service.GetReport(startDate, endDate)
+	report, err := h.service.GetReport(r.Context(), today, today)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(report)
-}
-
-func (h *TransactionHandler) HandleReport(w http.ResponseWriter, r *http.Request) {
-	switch r.Method {
-	case http.MethodGet:
-		h.Report(w, r)
-	default:
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-	}
+	writeJSON(w, http.StatusOK, report)
 }
 
 func (h *TransactionHandler) Report(w http.ResponseWriter, r *http.Request) {
@@ -82,28 +54,28 @@ func (h *TransactionHandler) Report(w http.ResponseWriter, r *http.Request) {
 	endDateStr := r.URL.Query().Get("end_date")
 
 	if startDateStr == "" || endDateStr == "" {
-		http.Error(w, "start_date and end_date are required", http.StatusBadRequest)
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "start_date and end_date are required", nil)
 		return
 	}
 
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	// Tanggal dibaca di zona waktu bisnis agar satu hari laporan dimulai dari tengah malam lokal.
+	startDate, err := time.ParseInLocation("2006-01-02", startDateStr, h.location)
 	if err != nil {
-		http.Error(w, "Invalid start_date format, use YYYY-MM-DD", http.StatusBadRequest)
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid start_date format, use YYYY-MM-DD", nil)
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	endDate, err := time.ParseInLocation("2006-01-02", endDateStr, h.location)
 	if err != nil {
-		http.Error(w, "Invalid end_date format, use YYYY-MM-DD", http.StatusBadRequest)
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid end_date format, use YYYY-MM-DD", nil)
 		return
 	}
 
-	report, err := h.service.GetReport(startDate, endDate)
+	report, err := h.service.GetReport(r.Context(), startDate, endDate)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(report)
+	writeJSON(w, http.StatusOK, report)
 }
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"task-session-1/models"
+	"task-session-1/services"
+)
+
+// VariantHandler adalah struct yang menangani request HTTP untuk varian produk.
+// Semua route berada di bawah /api/product/{id}/variants.
+type VariantHandler struct {
+	service *services.VariantService
+}
+
+// NewVariantHandler adalah konstruktor untuk membuat instance VariantHandler.
+func NewVariantHandler(service *services.VariantService) *VariantHandler {
+	return &VariantHandler{service: service}
+}
+
+// List menangani GET /api/product/{id}/variants untuk mengambil semua varian aktif produk.
+// Response berupa {"items": [...]}.
+func (h *VariantHandler) List(w http.ResponseWriter, r *http.Request) {
+	productID, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
+		return
+	}
+
+	variants, err := h.service.List(r.Context(), productID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": variants})
+}
+
+// Create menangani POST /api/product/{id}/variants untuk membuat varian baru.
+// Body berisi options (misalnya {"size": "M"}), serta sku, price, dan stock yang opsional.
+// Tanpa price, varian memakai harga produk.
+func (h *VariantHandler) Create(w http.ResponseWriter, r *http.Request) {
+	productID, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
+		return
+	}
+
+	var variant models.ProductVariant
+	if err := json.NewDecoder(r.Body).Decode(&variant); err != nil {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid request body", nil)
+		return
+	}
+
+	if err := h.service.Create(r.Context(), productID, &variant); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, variant)
+}
+
+// Update menangani PUT /api/product/{id}/variants/{variant_id} untuk memperbarui varian.
+// Seperti PUT produk, semua field ditimpa dan field stock ditolak dengan 422 karena stok hanya bisa diubah lewat
+// penyesuaian stok; price yang tidak dikirim berarti varian memakai harga produk.
+func (h *VariantHandler) Update(w http.ResponseWriter, r *http.Request) {
+	productID, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
+		return
+	}
+	variantID, ok := pathInt(r, "variant_id")
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid variant id", nil)
+		return
+	}
+
+	var variant models.ProductVariant
+	if err := decodeReplacement(r, &variant); err != nil {
+		writePatchError(w, r, err)
+		return
+	}
+
+	variant.ID = variantID
+	if err := h.service.Update(r.Context(), productID, &variant); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, variant)
+}
+
+// Delete menangani DELETE /api/product/{id}/variants/{variant_id} untuk menghapus varian (soft delete).
+// Transaksi lama yang merujuk varian tersebut tetap utuh.
+func (h *VariantHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	productID, ok := pathID(r)
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid product id", nil)
+		return
+	}
+	variantID, ok := pathInt(r, "variant_id")
+	if !ok {
+		writeErrorResponse(w, r, http.StatusBadRequest, codeBadRequest, "invalid variant id", nil)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), productID, variantID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{
+		"message": "variant deleted successfully",
+	})
+}
@@ -3,101 +3,175 @@ package main
 import (
 	"fmt"
 	"log"
-	"net/http"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"strings"
+	_ "time/tzdata"
 
-	"github.com/joho/godotenv"
-	"github.com/spf13/viper"
-
-	"task-session-1/database"
+	"task-session-1/config"
 	"task-session-1/handlers"
-	"task-session-1/repositories"
+	"task-session-1/notify"
 	"task-session-1/services"
 )
 
-// Config adalah struct yang menyimpan konfigurasi aplikasi.
-// Port adalah port tempat server akan berjalan.
-// DBConn adalah string koneksi ke database PostgreSQL.
-type Config struct {
-	Port   string `mapstructure:"PORT"`
-	DBConn string `mapstructure:"DB_CONN"`
-}
+// version adalah versi build aplikasi, diisi saat build dengan
+// go build -ldflags "-X main.version=v1.2.3". Jika kosong, revisi VCS dari build info dipakai.
+var version = ""
 
 // main adalah fungsi utama yang dijalankan saat aplikasi dimulai.
 // Fungsi ini melakukan inisialisasi, validasi konfigurasi, dan menjalankan server HTTP.
 func main() {
-	// Memuat file .env jika ada. Jika tidak ada, menggunakan environment variables sistem.
-	// Ini memungkinkan konfigurasi fleksibel antara development dan production.
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system env")
+	// Memuat konfigurasi dari file, environment variables, dan flag command-line.
+	// Jika ada konfigurasi yang tidak valid, aplikasi berhenti dan menampilkan semua key yang bermasalah.
+	cfg, args, err := config.Load(os.Args[1:])
+	if config.IsHelp(err) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	// Mengaktifkan pembacaan otomatis dari environment variables menggunakan viper.
-	viper.AutomaticEnv()
+	// Mengatur logger default sesuai LOG_LEVEL. Pemanggilan package log juga diteruskan ke logger ini.
+	setupLogger(cfg.LogLevel)
 
-	// Membuat instance Config dan mengisi dengan nilai dari environment variables.
-	config := Config{
-		Port:   viper.GetString("PORT"),
-		DBConn: viper.GetString("DB_CONN"),
+	// Subcommand "migrate" hanya menjalankan migrasi lalu keluar, tanpa menjalankan server.
+	// Contoh: go run . migrate up | go run . migrate down 1 | go run . migrate status
+	if len(args) > 0 && args[0] == "migrate" {
+		if cfg.Storage != config.StorageDatabase {
+			log.Fatal("migrate requires STORAGE=database")
+		}
+		if err := runMigrateCommand(cfg, args[1:]); err != nil {
+			log.Fatal("migrate: ", err)
+		}
+		return
 	}
 
-	// Validasi bahwa konfigurasi PORT dan DB_CONN tidak kosong.
-	// Jika kosong, aplikasi akan berhenti karena tidak bisa berjalan tanpa konfigurasi ini.
-	if config.Port == "" {
-		log.Fatal("PORT is required")
-	}
-	if config.DBConn == "" {
-		log.Fatal("DB_CONN is required")
+	// Subcommand "stock reconcile" memeriksa apakah stok setiap produk dan varian sama dengan ledger stok.
+	// Contoh: go run . stock reconcile
+	if len(args) > 0 && args[0] == "stock" {
+		if cfg.Storage != config.StorageDatabase {
+			log.Fatal("stock requires STORAGE=database")
+		}
+		if err := runStockCommand(cfg, args[1:]); err != nil {
+			log.Fatal("stock: ", err)
+		}
+		return
 	}
 
-	// Inisialisasi koneksi database menggunakan fungsi InitDB dari package database.
-	// Jika gagal, aplikasi akan berhenti karena tidak bisa mengakses database.
-	db, err := database.InitDB(config.DBConn)
+	// Membuka storage (database atau in-memory) beserta semua repository-nya.
+	// Jika gagal, aplikasi akan berhenti karena tidak bisa mengakses data.
+	store, err := openStorage(cfg)
 	if err != nil {
-		log.Fatal("Failed to initialize database:", err)
+		log.Fatal("Failed to initialize storage:", err)
 	}
-	// Pastikan koneksi database ditutup saat aplikasi selesai.
-	defer db.Close()
-
-	// Membuat alamat server berdasarkan port yang dikonfigurasi.
-	addr := "0.0.0.0:" + config.Port
-	fmt.Println("Server running di", addr)
 
 	// Inisialisasi komponen untuk kategori: repository, service, dan handler.
 	// Repository mengakses database, service menangani logika bisnis, handler menangani HTTP requests.
-	categoryRepo := repositories.NewCategoryRepository(db)
-	categoryService := services.NewCategoryService(categoryRepo)
+	categoryService := services.NewCategoryService(store.categories)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
 
 	// Inisialisasi komponen untuk produk: repository, service, dan handler.
-	// Product service membutuhkan category repository juga untuk validasi.
-	productRepo := repositories.NewProductRepository(db)
-	productService := services.NewProductService(productRepo, categoryRepo)
+	// Product service membutuhkan category repository juga untuk validasi, dan variant repository untuk include=variants.
+	productService := services.NewProductService(store.products, store.categories, store.variants)
 	productHandler := handlers.NewProductHandler(productService)
 
+	// Varian produk (ukuran, warna, dan sebagainya) dengan stok dan harga sendiri.
+	variantService := services.NewVariantService(store.variants, store.products)
+	variantHandler := handlers.NewVariantHandler(variantService)
+
+	// Ledger stok: riwayat perubahan stok per produk dan penyesuaian stok di luar checkout.
+	stockService := services.NewStockService(store.movements, store.products, store.variants)
+	stockHandler := handlers.NewStockHandler(stockService)
+
+	// Supplier dan purchase order: penerimaan barang menambah stok dan dicatat di ledger stok.
+	supplierService := services.NewSupplierService(store.suppliers)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+	purchaseOrderService := services.NewPurchaseOrderService(store.orders, store.suppliers, store.products, store.variants)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderService)
+
+	// Stock opname: posting sesi mengoreksi stok dan dicatat di ledger stok.
+	stockCountService := services.NewStockCountService(store.stockCounts, store.categories)
+	stockCountHandler := handlers.NewStockCountHandler(stockCountService)
+
+	// Peringatan stok menipis saat checkout dikirim ke tujuan dari LOW_STOCK_NOTIFIER (log, webhook, atau none).
+	lowStockNotifier, err := notify.New(cfg.LowStockNotifier, cfg.LowStockWebhookURL)
+	if err != nil {
+		log.Fatal("Failed to initialize low stock notifier:", err)
+	}
+
 	// Transaction
-	transactionRepo := repositories.NewTransactionRepository(db)
-	transactionService := services.NewTransactionService(transactionRepo)
-	transactionHandler := handlers.NewTransactionHandler(transactionService)
-
-	// Mendaftarkan handler untuk endpoint HTTP.
-	// /api/category untuk operasi umum kategori (GET semua, POST buat baru).
-	http.HandleFunc("/api/category", categoryHandler.HandleCategory)
-	// /api/category/ untuk operasi berdasarkan ID (GET, PUT, DELETE).
-	http.HandleFunc("/api/category/", categoryHandler.HandleCategoryByID)
-	// /api/product untuk operasi produk (GET semua, POST buat baru).
-	http.HandleFunc("/api/product", productHandler.HandleProduct)
-	// /api/product/ untuk operasi berdasarkan ID (GET, PUT, DELETE).
-	http.HandleFunc("/api/product/", productHandler.HandleProductByID)
-	// /api/checkout
-	http.HandleFunc("/api/checkout", transactionHandler.HandleCheckout)
-	// /api/report/hari-ini
-	http.HandleFunc("/api/report/hari-ini", transactionHandler.HandleReportHariIni)
-	// /api/report
-	http.HandleFunc("/api/report", transactionHandler.HandleReport)
-
-	// Menjalankan server HTTP di alamat yang ditentukan.
-	// Jika gagal, aplikasi akan berhenti dengan pesan error.
-	if err := http.ListenAndServe(addr, nil); err != nil {
-		log.Fatal("gagal running server:", err)
+	// Laporan "hari ini" dihitung berdasarkan zona waktu bisnis dari TIMEZONE.
+	transactionService := services.NewTransactionService(store.transactions, lowStockNotifier)
+	transactionHandler := handlers.NewTransactionHandler(transactionService, cfg.Location())
+
+	// Endpoint health untuk load balancer dan orchestrator, serta diagnostik untuk admin.
+	healthHandler := handlers.NewHealthHandler(store.db, buildVersion(), cfg.Summary())
+
+	// Mendaftarkan semua route beserta middleware (request ID, logging, recovery).
+	// /api/checkout dan /api/report hanya didaftarkan jika fiturnya aktif.
+	router := handlers.NewRouter(handlers.RouterConfig{
+		Category:        categoryHandler,
+		Product:         productHandler,
+		Variant:         variantHandler,
+		Stock:           stockHandler,
+		Supplier:        supplierHandler,
+		PurchaseOrder:   purchaseOrderHandler,
+		StockCount:      stockCountHandler,
+		Transaction:     transactionHandler,
+		Health:          healthHandler,
+		AdminToken:      cfg.AdminToken,
+		FeatureCheckout: cfg.FeatureCheckout,
+		FeatureReports:  cfg.FeatureReports,
+	})
+
+	// Membuat http.Server dengan timeout dari konfigurasi.
+	server := newServer(cfg, router)
+	fmt.Println("Server running di", server.Addr)
+
+	// Menjalankan server HTTP sampai menerima sinyal berhenti.
+	// Koneksi database baru ditutup setelah semua request yang sedang berjalan selesai.
+	serveErr := serve(server, cfg.ShutdownTimeout)
+	if err := store.Close(); err != nil {
+		log.Println("Failed to close storage:", err)
+	}
+	if serveErr != nil {
+		log.Fatal("gagal running server:", serveErr)
+	}
+}
+
+// buildVersion mengembalikan versi build dari ldflags, atau revisi VCS jika tersedia.
+func buildVersion() string {
+	if version != "" {
+		return version
 	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return "dev"
+}
+
+// setupLogger menjadikan slog dengan level dari konfigurasi sebagai logger default.
+func setupLogger(level string) {
+	var lvl slog.Level
+	switch strings.ToLower(level) {
+	case "debug":
+		lvl = slog.LevelDebug
+	case "warn":
+		lvl = slog.LevelWarn
+	case "error":
+		lvl = slog.LevelError
+	default:
+		lvl = slog.LevelInfo
+	}
+
+	handler := slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: lvl})
+	slog.SetDefault(slog.New(handler))
 }
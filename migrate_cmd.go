@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"task-session-1/config"
+	"task-session-1/database"
+)
+
+// runMigrateCommand menjalankan subcommand migrasi: up, down [n], atau status.
+// Subcommand ini memungkinkan migrasi dijalankan sesuai kebutuhan tanpa menyalakan server.
+func runMigrateCommand(cfg *config.Config, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	db, err := database.InitDB(databaseConfig(cfg))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+
+	switch action {
+	case "up":
+		return database.Migrate(ctx, db)
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				return fmt.Errorf("invalid steps %q", args[1])
+			}
+		}
+		return database.MigrateDown(ctx, db, steps)
+	case "status":
+		statuses, err := database.Status(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				state = "applied"
+			}
+			fmt.Printf("%04d  %-30s %s\n", s.Version, s.Name, state)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q, use up, down [n] or status", action)
+	}
+}
@@ -0,0 +1,30 @@
+package models
+
+// Panjang barcode yang didukung: UPC-A (12 digit) dan EAN-13 (13 digit).
+const (
+	upcALength  = 12
+	ean13Length = 13
+)
+
+// ValidBarcode memeriksa bahwa code adalah barcode UPC-A atau EAN-13 dengan check digit yang benar.
+// Keduanya memakai algoritma GTIN: dari digit paling kanan sebelum check digit, bobot 3 dan 1 bergantian,
+// dan check digit membuat jumlah total habis dibagi 10.
+func ValidBarcode(code string) bool {
+	if len(code) != upcALength && len(code) != ean13Length {
+		return false
+	}
+
+	sum := 0
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+		digit := int(code[i] - '0')
+		// Posisi dihitung dari kanan; check digit ada di posisi 0 dengan bobot 1.
+		if (len(code)-1-i)%2 == 1 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return sum%10 == 0
+}
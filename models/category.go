@@ -1,7 +1,39 @@
 package models
 
+import "time"
+
+// Category adalah data kategori produk.
+// ProductCount adalah jumlah produk aktif (belum dihapus) di kategori ini, dihitung saat kategori dibaca.
+// DeletedAt terisi jika kategori sudah dihapus (soft delete).
 type Category struct {
-	ID int `json:"id"`
-	Name string `json:"name"`
-	Description string `json:"description"`
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	ProductCount int        `json:"product_count"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+}
+
+// CategoryOptions mengatur kategori mana yang ikut diambil.
+// IncludeDeleted ikut mengambil kategori yang sudah dihapus (soft delete).
+type CategoryOptions struct {
+	IncludeDeleted bool
+}
+
+// CategoryDeleteMode menentukan apa yang terjadi pada produk aktif saat kategorinya dihapus.
+type CategoryDeleteMode string
+
+const (
+	// CategoryDeleteRestrict menolak penghapusan jika kategori masih punya produk aktif (default).
+	CategoryDeleteRestrict CategoryDeleteMode = "restrict"
+	// CategoryDeleteReassign memindahkan produk ke kategori lain sebelum kategori dihapus.
+	CategoryDeleteReassign CategoryDeleteMode = "reassign"
+	// CategoryDeleteCascade ikut menghapus (soft delete) semua produk aktif di kategori tersebut.
+	CategoryDeleteCascade CategoryDeleteMode = "cascade"
+)
+
+// CategoryDeletePolicy berisi mode penghapusan kategori.
+// TargetCategoryID adalah kategori tujuan produk dan hanya dipakai pada mode reassign.
+type CategoryDeletePolicy struct {
+	Mode             CategoryDeleteMode
+	TargetCategoryID int
 }
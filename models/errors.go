@@ -0,0 +1,115 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Error sentinel untuk kategori kesalahan domain.
+// Service dan repository membungkus error ini (langsung atau melalui tipe error di bawah)
+// sehingga handler bisa memetakan error ke status HTTP dengan errors.Is.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrValidation        = errors.New("validation failed")
+	ErrConflict          = errors.New("conflict")
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+// NotFoundError menandakan data dengan ID tertentu tidak ditemukan.
+type NotFoundError struct {
+	Resource string
+	ID       interface{}
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %v not found", e.Resource, e.ID)
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return ErrNotFound
+}
+
+// ValidationError berisi pesan kesalahan per field, dengan key berupa nama field JSON.
+type ValidationError struct {
+	Fields map[string]string
+}
+
+// NewValidationError membuat ValidationError untuk satu field.
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Fields: map[string]string{field: message}}
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, 0, len(fields))
+	for _, field := range fields {
+		parts = append(parts, field+" "+e.Fields[field])
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// ConflictError menandakan operasi bertentangan dengan data yang ada,
+// misalnya menghapus data yang masih direferensikan data lain.
+// Details berisi informasi tambahan untuk client (boleh nil).
+type ConflictError struct {
+	Message string
+	Details map[string]interface{}
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// InsufficientStockError menandakan stok produk (atau varian, jika VariantID diisi) tidak cukup untuk jumlah yang diminta.
+type InsufficientStockError struct {
+	ProductID int
+	VariantID int
+	Requested int
+	Available int
+}
+
+func (e *InsufficientStockError) Error() string {
+	if e.VariantID != 0 {
+		return fmt.Sprintf("insufficient stock for product id %d variant id %d: requested %d, available %d", e.ProductID, e.VariantID, e.Requested, e.Available)
+	}
+	return fmt.Sprintf("insufficient stock for product id %d: requested %d, available %d", e.ProductID, e.Requested, e.Available)
+}
+
+func (e *InsufficientStockError) Unwrap() error {
+	return ErrInsufficientStock
+}
+
+// ImportRowError berisi pesan kesalahan per kolom untuk satu baris CSV.
+type ImportRowError struct {
+	Line   int               `json:"line"`
+	Fields map[string]string `json:"errors"`
+}
+
+// ImportError menandakan satu atau lebih baris import tidak valid.
+// Import bersifat all-or-nothing, sehingga tidak ada baris yang disimpan.
+type ImportError struct {
+	Rows []ImportRowError
+}
+
+func (e *ImportError) Error() string {
+	return fmt.Sprintf("import failed: %d invalid rows", len(e.Rows))
+}
+
+func (e *ImportError) Unwrap() error {
+	return ErrValidation
+}
@@ -0,0 +1,33 @@
+package models
+
+// MaxImportRows adalah batas jumlah baris data (tanpa header) dalam satu file import CSV, baik import produk
+// maupun hasil hitung stock opname.
+const MaxImportRows = 5000
+
+// Aksi yang dilakukan import untuk satu baris CSV.
+const (
+	ImportCreate = "create"
+	ImportUpdate = "update"
+)
+
+// ProductImportRow adalah hasil import untuk satu baris CSV.
+// Line adalah nomor baris di file (header adalah baris 1). Action bernilai "create" atau "update";
+// produk dengan SKU yang sudah dipakai produk aktif diperbarui, selain itu dibuat baru.
+// ProductID kosong untuk produk baru pada dry run karena belum disimpan.
+type ProductImportRow struct {
+	Line      int    `json:"line"`
+	Action    string `json:"action"`
+	ProductID int    `json:"product_id,omitempty"`
+	SKU       string `json:"sku,omitempty"`
+	Name      string `json:"name"`
+}
+
+// ProductImportResult adalah ringkasan import produk.
+// Jika DryRun bernilai true, tidak ada data yang disimpan.
+type ProductImportResult struct {
+	DryRun  bool               `json:"dry_run"`
+	Total   int                `json:"total"`
+	Created int                `json:"created"`
+	Updated int                `json:"updated"`
+	Rows    []ProductImportRow `json:"rows"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// IsLowStock melaporkan apakah stok sudah mencapai batas pemesanan ulang reorderLevel.
+// reorderLevel 0 berarti peringatan stok menipis nonaktif.
+func IsLowStock(stock, reorderLevel int) bool {
+	return reorderLevel > 0 && stock <= reorderLevel
+}
+
+// LowStockItem adalah produk, atau varian produk jika VariantID diisi, yang stoknya sudah mencapai
+// batas pemesanan ulang. Untuk varian, ReorderLevel dan ReorderQty diambil dari produknya.
+type LowStockItem struct {
+	ProductID    int    `json:"product_id"`
+	VariantID    *int   `json:"variant_id,omitempty"`
+	Name         string `json:"name"`
+	VariantName  string `json:"variant_name,omitempty"`
+	SKU          string `json:"sku,omitempty"`
+	CategoryID   int    `json:"category_id"`
+	Stock        int    `json:"stock"`
+	ReorderLevel int    `json:"reorder_level"`
+	ReorderQty   int    `json:"reorder_qty"`
+}
+
+// LowStockFilter berisi filter daftar stok menipis. CategoryID 0 berarti semua kategori.
+type LowStockFilter struct {
+	CategoryID int
+}
+
+// Validate memeriksa filter daftar stok menipis.
+func (f LowStockFilter) Validate() error {
+	var v validator
+	v.check(f.CategoryID >= 0, "category_id", "must be positive")
+	return v.err()
+}
+
+// LowStockEvent adalah peringatan yang dikirim ke notifier saat penjualan membuat stok produk atau varian
+// turun dari atas batas pemesanan ulang ke batas tersebut atau di bawahnya.
+type LowStockEvent struct {
+	LowStockItem
+	TransactionID int       `json:"transaction_id"`
+	OccurredAt    time.Time `json:"occurred_at"`
+}
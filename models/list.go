@@ -0,0 +1,228 @@
+package models
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Batas jumlah data per halaman pada endpoint daftar.
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Page adalah envelope response untuk daftar data yang dipaginasi.
+// NextCursor kosong jika tidak ada halaman berikutnya.
+// Total adalah jumlah seluruh data yang cocok dengan filter, tanpa memperhitungkan paginasi.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int    `json:"total"`
+}
+
+// ListOptions berisi pengaturan paginasi dan pengurutan yang dipakai bersama oleh semua endpoint daftar.
+// Paginasi bisa memakai Offset atau Cursor, tetapi tidak keduanya sekaligus.
+// Sort adalah nama field pengurutan (misalnya "price"); Desc membalik urutannya.
+// Data dengan nilai Sort yang sama selalu diurutkan berdasarkan ID agar urutannya stabil.
+type ListOptions struct {
+	Limit  int
+	Offset int
+	Cursor *Cursor
+	Sort   string
+	Desc   bool
+}
+
+// SortKey mengembalikan pengurutan dalam format query string, misalnya "price" atau "-price".
+func (o *ListOptions) SortKey() string {
+	if o.Desc {
+		return "-" + o.Sort
+	}
+	return o.Sort
+}
+
+// SetSort mengisi Sort dan Desc dari format query string seperti "name" atau "-price".
+func (o *ListOptions) SetSort(key string) {
+	o.Sort, o.Desc = strings.CutPrefix(key, "-")
+	if o.Sort == "" {
+		o.Sort, o.Desc = "id", false
+	}
+}
+
+// validate memeriksa limit, offset, field pengurutan, dan cursor.
+// sortFields berisi field yang boleh dipakai untuk mengurutkan, dengan nilai true untuk field numerik.
+// Nilai cursor dinormalisasi ke int atau string sesuai tipe field-nya.
+func (o *ListOptions) validate(v *validator, sortFields map[string]bool) {
+	v.check(o.Limit >= 1 && o.Limit <= MaxPageLimit, "limit", fmt.Sprintf("must be between 1 and %d", MaxPageLimit))
+	v.check(o.Offset >= 0, "offset", "must not be negative")
+
+	numeric, ok := sortFields[o.Sort]
+	if !ok {
+		v.add("sort", "unsupported sort field "+o.Sort)
+		return
+	}
+
+	if o.Cursor == nil {
+		return
+	}
+	v.check(o.Offset == 0, "offset", "cannot be combined with cursor")
+	if o.Cursor.Sort != o.SortKey() {
+		v.add("cursor", "was issued for a different sort order")
+		return
+	}
+	if err := o.Cursor.normalize(numeric); err != nil {
+		v.add("cursor", "is invalid")
+	}
+}
+
+// Cursor menandai posisi data terakhir pada halaman sebelumnya untuk paginasi berbasis keyset.
+// Value adalah nilai field pengurutan data terakhir dan ID adalah ID-nya sebagai pemecah seri.
+type Cursor struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v"`
+	ID    int         `json:"id"`
+}
+
+// EncodeCursor mengubah cursor menjadi token yang aman dipakai di query string.
+func EncodeCursor(c Cursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor membaca token hasil EncodeCursor.
+// Value masih berupa json.Number atau string sampai divalidasi bersama ListOptions.
+func DecodeCursor(token string) (*Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cursor
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&c); err != nil {
+		return nil, err
+	}
+	if c.ID <= 0 {
+		return nil, fmt.Errorf("invalid cursor id %d", c.ID)
+	}
+	return &c, nil
+}
+
+// normalize mengubah Value hasil decode menjadi int untuk field numerik, atau string untuk field teks.
+func (c *Cursor) normalize(numeric bool) error {
+	switch value := c.Value.(type) {
+	case json.Number:
+		if !numeric {
+			return fmt.Errorf("cursor value must be a string")
+		}
+		n, err := value.Int64()
+		if err != nil {
+			return err
+		}
+		c.Value = int(n)
+	case int:
+		if !numeric {
+			return fmt.Errorf("cursor value must be a string")
+		}
+	case string:
+		if numeric {
+			return fmt.Errorf("cursor value must be a number")
+		}
+	default:
+		return fmt.Errorf("unsupported cursor value %T", c.Value)
+	}
+	return nil
+}
+
+// productSortFields adalah field yang boleh dipakai untuk mengurutkan produk (true = numerik).
+// "relevance" adalah skor pencarian dan hanya bisa dipakai bersama parameter q.
+var productSortFields = map[string]bool{
+	"id":        true,
+	"name":      false,
+	"price":     true,
+	"stock":     true,
+	"relevance": true,
+}
+
+// categorySortFields adalah field yang boleh dipakai untuk mengurutkan kategori (true = numerik).
+var categorySortFields = map[string]bool{
+	"id":   true,
+	"name": false,
+}
+
+// ProductFilter berisi filter, paginasi, dan pengurutan untuk daftar produk.
+// Field pointer bernilai nil berarti filter tersebut tidak dipakai.
+// Query mengaktifkan pencarian berperingkat di nama produk, SKU, dan nama kategori (lihat SearchScore).
+type ProductFilter struct {
+	ListOptions
+	ProductOptions
+
+	Query      string
+	Name       string
+	CategoryID int
+	MinPrice   *int
+	MaxPrice   *int
+	InStock    *bool
+}
+
+// Validate memeriksa filter dan paginasi produk. Pelanggaran memakai key nama parameter query.
+func (f *ProductFilter) Validate() error {
+	var v validator
+	f.ListOptions.validate(&v, productSortFields)
+	v.check(f.CategoryID >= 0, "category_id", "must be positive")
+	if f.Query != "" {
+		v.maxLength(f.Query, "q", MaxSearchQueryLength)
+		v.check(len(SearchTerms(f.Query)) > 0, "q", "must contain letters or digits")
+	} else if f.Sort == "relevance" {
+		v.add("sort", "relevance requires q")
+	}
+	if f.MinPrice != nil && f.MaxPrice != nil {
+		v.check(*f.MinPrice <= *f.MaxPrice, "min_price", "must not be greater than max_price")
+	}
+	return v.err()
+}
+
+// CursorFor membuat cursor yang menunjuk ke produk p sesuai pengurutan filter.
+func (f *ProductFilter) CursorFor(p Product) string {
+	var value interface{}
+	switch f.Sort {
+	case "name":
+		value = p.Name
+	case "price":
+		value = p.Price
+	case "stock":
+		value = p.Stock
+	case "relevance":
+		value = p.Score
+	default:
+		value = p.ID
+	}
+	return EncodeCursor(Cursor{Sort: f.SortKey(), Value: value, ID: p.ID})
+}
+
+// CategoryFilter berisi filter, paginasi, dan pengurutan untuk daftar kategori.
+type CategoryFilter struct {
+	ListOptions
+	CategoryOptions
+
+	Name string
+}
+
+// Validate memeriksa filter dan paginasi kategori. Pelanggaran memakai key nama parameter query.
+func (f *CategoryFilter) Validate() error {
+	var v validator
+	f.ListOptions.validate(&v, categorySortFields)
+	return v.err()
+}
+
+// CursorFor membuat cursor yang menunjuk ke kategori c sesuai pengurutan filter.
+func (f *CategoryFilter) CursorFor(c Category) string {
+	var value interface{} = c.ID
+	if f.Sort == "name" {
+		value = c.Name
+	}
+	return EncodeCursor(Cursor{Sort: f.SortKey(), Value: value, ID: c.ID})
+}
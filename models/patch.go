@@ -0,0 +1,107 @@
+package models
+
+// ProductPatch berisi perubahan sebagian pada produk (JSON Merge Patch).
+// Field bernilai nil berarti tidak diubah; hanya field yang diisi yang ditulis ke database.
+// SKU atau Barcode berisi string kosong menghapus kode tersebut dari produk.
+// Stok tidak bisa diubah lewat patch; gunakan penyesuaian stok (StockAdjustment).
+type ProductPatch struct {
+	Name         *string `json:"name"`
+	Price        *int    `json:"price"`
+	CategoryID   *int    `json:"category_id"`
+	SKU          *string `json:"sku"`
+	Barcode      *string `json:"barcode"`
+	ReorderLevel *int    `json:"reorder_level"`
+	ReorderQty   *int    `json:"reorder_qty"`
+}
+
+// IsEmpty mengembalikan true jika patch tidak mengubah field apa pun.
+func (p *ProductPatch) IsEmpty() bool {
+	return p.Name == nil && p.Price == nil && p.CategoryID == nil &&
+		p.SKU == nil && p.Barcode == nil && p.ReorderLevel == nil && p.ReorderQty == nil
+}
+
+// Validate memeriksa field yang diisi dengan aturan yang sama seperti Product.Validate.
+func (p *ProductPatch) Validate() error {
+	var v validator
+	if p.Name != nil {
+		v.required(*p.Name, "name", MaxProductNameLength)
+	}
+	if p.Price != nil {
+		v.check(*p.Price >= 0, "price", "must not be negative")
+	}
+	if p.CategoryID != nil {
+		v.check(*p.CategoryID > 0, "category_id", "must be positive")
+	}
+	if p.SKU != nil {
+		v.sku(*p.SKU, "sku")
+	}
+	if p.Barcode != nil {
+		v.barcode(*p.Barcode, "barcode")
+	}
+	if p.ReorderLevel != nil {
+		v.check(*p.ReorderLevel >= 0, "reorder_level", "must not be negative")
+	}
+	if p.ReorderQty != nil {
+		v.check(*p.ReorderQty >= 0, "reorder_qty", "must not be negative")
+	}
+	return v.err()
+}
+
+// Apply menerapkan field yang diisi ke product.
+func (p *ProductPatch) Apply(product *Product) {
+	if p.Name != nil {
+		product.Name = *p.Name
+	}
+	if p.Price != nil {
+		product.Price = *p.Price
+	}
+	if p.CategoryID != nil {
+		product.CategoryID = *p.CategoryID
+	}
+	if p.SKU != nil {
+		product.SKU = *p.SKU
+	}
+	if p.Barcode != nil {
+		product.Barcode = *p.Barcode
+	}
+	if p.ReorderLevel != nil {
+		product.ReorderLevel = *p.ReorderLevel
+	}
+	if p.ReorderQty != nil {
+		product.ReorderQty = *p.ReorderQty
+	}
+}
+
+// CategoryPatch berisi perubahan sebagian pada kategori (JSON Merge Patch).
+// Field bernilai nil berarti tidak diubah.
+type CategoryPatch struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+}
+
+// IsEmpty mengembalikan true jika patch tidak mengubah field apa pun.
+func (p *CategoryPatch) IsEmpty() bool {
+	return p.Name == nil && p.Description == nil
+}
+
+// Validate memeriksa field yang diisi dengan aturan yang sama seperti Category.Validate.
+func (p *CategoryPatch) Validate() error {
+	var v validator
+	if p.Name != nil {
+		v.required(*p.Name, "name", MaxCategoryNameLength)
+	}
+	if p.Description != nil {
+		v.maxLength(*p.Description, "description", MaxCategoryDescriptionLength)
+	}
+	return v.err()
+}
+
+// Apply menerapkan field yang diisi ke category.
+func (p *CategoryPatch) Apply(category *Category) {
+	if p.Name != nil {
+		category.Name = *p.Name
+	}
+	if p.Description != nil {
+		category.Description = *p.Description
+	}
+}
@@ -1,11 +1,38 @@
 package models
 
+import "time"
+
+// Product adalah data produk yang dijual.
+// SKU (kode internal toko) dan Barcode (EAN-13 atau UPC-A) bersifat opsional,
+// tetapi jika diisi harus unik di antara produk aktif.
+// DeletedAt terisi jika produk sudah dihapus (soft delete); produk yang dihapus tetap ada
+// di database agar transaksi dan laporan lama masih bisa menampilkan namanya.
+// ReorderLevel adalah batas stok untuk peringatan stok menipis (0 = nonaktif) dan ReorderQty jumlah yang
+// disarankan untuk dipesan ulang; untuk produk yang punya varian, batas tersebut berlaku per varian.
+// Score adalah skor relevansi dan hanya terisi pada hasil pencarian (parameter q).
 type Product struct {
-	ID         int    `json:"id"`
-	Name       string `json:"name"`
-	Price      int    `json:"price"`
-	Stock      int    `json:"stock"`
-	CategoryID int    `json:"category_id"`
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	Price        int        `json:"price"`
+	Stock        int        `json:"stock"`
+	CategoryID   int        `json:"category_id"`
+	SKU          string     `json:"sku,omitempty"`
+	Barcode      string     `json:"barcode,omitempty"`
+	ReorderLevel int        `json:"reorder_level"`
+	ReorderQty   int        `json:"reorder_qty"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty"`
+	Score        int        `json:"score,omitempty"`
+
+	Category *Category        `json:"category,omitempty"`
+	Variants []ProductVariant `json:"variants,omitempty"`
+}
 
-	Category *Category `json:"category,omitempty"`
+// ProductOptions mengatur data tambahan yang ikut diambil bersama produk.
+// IncludeCategory mengisi field Category dari JOIN ke tabel category dalam query yang sama.
+// IncludeVariants mengisi field Variants dengan varian aktif produk.
+// IncludeDeleted ikut mengambil produk yang sudah dihapus (soft delete).
+type ProductOptions struct {
+	IncludeCategory bool
+	IncludeVariants bool
+	IncludeDeleted  bool
 }
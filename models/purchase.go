@@ -0,0 +1,220 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Supplier adalah pemasok barang yang menerima purchase order.
+type Supplier struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	ContactName string `json:"contact_name"`
+	Phone       string `json:"phone"`
+	Email       string `json:"email"`
+}
+
+// PurchaseOrderStatus adalah status purchase order.
+type PurchaseOrderStatus string
+
+const (
+	// PurchaseOrderOrdered adalah purchase order yang belum menerima barang sama sekali.
+	PurchaseOrderOrdered PurchaseOrderStatus = "ordered"
+	// PurchaseOrderPartiallyReceived adalah purchase order yang sebagian barangnya sudah diterima.
+	PurchaseOrderPartiallyReceived PurchaseOrderStatus = "partially_received"
+	// PurchaseOrderReceived adalah purchase order yang semua barangnya sudah diterima.
+	PurchaseOrderReceived PurchaseOrderStatus = "received"
+	// PurchaseOrderCancelled adalah purchase order yang dibatalkan; sisa barangnya tidak akan diterima.
+	PurchaseOrderCancelled PurchaseOrderStatus = "cancelled"
+
+	// PurchaseOrderOpen bukan status yang disimpan, melainkan filter untuk purchase order yang masih menunggu
+	// barang (ordered dan partially_received).
+	PurchaseOrderOpen PurchaseOrderStatus = "open"
+)
+
+// IsOpen melaporkan apakah purchase order dengan status s masih bisa menerima barang atau dibatalkan.
+func (s PurchaseOrderStatus) IsOpen() bool {
+	return s == PurchaseOrderOrdered || s == PurchaseOrderPartiallyReceived
+}
+
+// NewPurchaseOrderClosedError membuat ConflictError untuk purchase order id yang sudah tidak open (status received
+// atau cancelled), sehingga tidak bisa lagi menerima barang atau dibatalkan.
+func NewPurchaseOrderClosedError(id int, status PurchaseOrderStatus) *ConflictError {
+	return &ConflictError{
+		Message: fmt.Sprintf("purchase order %d is %s", id, status),
+		Details: map[string]interface{}{"status": status},
+	}
+}
+
+// PurchaseOrder adalah pesanan pembelian ke supplier. ExpectedCost adalah total quantity × unit_cost semua baris,
+// dan ReceivedCost adalah total received_quantity × unit_cost; keduanya dihitung saat purchase order dibaca.
+type PurchaseOrder struct {
+	ID           int                 `json:"id"`
+	SupplierID   int                 `json:"supplier_id"`
+	SupplierName string              `json:"supplier_name,omitempty"`
+	Status       PurchaseOrderStatus `json:"status"`
+	Note         string              `json:"note"`
+	ExpectedCost int                 `json:"expected_cost"`
+	ReceivedCost int                 `json:"received_cost"`
+	Items        []PurchaseOrderItem `json:"items"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+// PurchaseOrderItem adalah satu baris purchase order untuk produk, atau varian produk jika VariantID diisi.
+// UnitCost adalah harga beli per unit yang diharapkan.
+type PurchaseOrderItem struct {
+	ID               int    `json:"id"`
+	ProductID        int    `json:"product_id"`
+	VariantID        *int   `json:"variant_id,omitempty"`
+	ProductName      string `json:"product_name,omitempty"`
+	Quantity         int    `json:"quantity"`
+	ReceivedQuantity int    `json:"received_quantity"`
+	UnitCost         int    `json:"unit_cost"`
+}
+
+// Remaining mengembalikan jumlah barang yang belum diterima.
+func (i *PurchaseOrderItem) Remaining() int {
+	return i.Quantity - i.ReceivedQuantity
+}
+
+// UpdateTotals menghitung ulang ExpectedCost dan ReceivedCost dari Items.
+func (po *PurchaseOrder) UpdateTotals() {
+	po.ExpectedCost, po.ReceivedCost = 0, 0
+	for _, item := range po.Items {
+		po.ExpectedCost += item.Quantity * item.UnitCost
+		po.ReceivedCost += item.ReceivedQuantity * item.UnitCost
+	}
+}
+
+// ReceivedStatus mengembalikan status purchase order berdasarkan barang yang sudah diterima di Items.
+func (po *PurchaseOrder) ReceivedStatus() PurchaseOrderStatus {
+	remaining, received := 0, 0
+	for _, item := range po.Items {
+		remaining += item.Remaining()
+		received += item.ReceivedQuantity
+	}
+	switch {
+	case remaining == 0:
+		return PurchaseOrderReceived
+	case received > 0:
+		return PurchaseOrderPartiallyReceived
+	default:
+		return PurchaseOrderOrdered
+	}
+}
+
+// ReceiptQuantities menghitung jumlah barang yang diterima per baris purchase order (indeks Items) dari receipt.
+// Receipt tanpa Items menerima semua sisa barang. Mengembalikan ValidationError jika item_id bukan baris
+// purchase order ini atau quantity melebihi sisa barang baris tersebut.
+func (po *PurchaseOrder) ReceiptQuantities(receipt PurchaseOrderReceipt) (map[int]int, error) {
+	quantities := make(map[int]int)
+	if len(receipt.Items) == 0 {
+		for i, item := range po.Items {
+			if item.Remaining() > 0 {
+				quantities[i] = item.Remaining()
+			}
+		}
+		return quantities, nil
+	}
+
+	var v validator
+	for i, r := range receipt.Items {
+		prefix := fmt.Sprintf("items[%d].", i)
+		index := slices.IndexFunc(po.Items, func(item PurchaseOrderItem) bool { return item.ID == r.ItemID })
+		if index < 0 {
+			v.add(prefix+"item_id", "is not an item of this purchase order")
+			continue
+		}
+		remaining := po.Items[index].Remaining()
+		v.check(r.Quantity <= remaining, prefix+"quantity", fmt.Sprintf("must not exceed the remaining quantity %d", remaining))
+		quantities[index] = r.Quantity
+	}
+	return quantities, v.err()
+}
+
+// PurchaseOrderReceipt adalah request penerimaan barang untuk purchase order. Items kosong berarti semua sisa
+// barang diterima sekaligus; jika diisi, hanya baris yang disebut yang diterima sebanyak Quantity.
+type PurchaseOrderReceipt struct {
+	Items []PurchaseOrderReceiptItem `json:"items"`
+}
+
+// PurchaseOrderReceiptItem adalah jumlah barang yang diterima untuk satu baris purchase order.
+type PurchaseOrderReceiptItem struct {
+	ItemID   int `json:"item_id"`
+	Quantity int `json:"quantity"`
+}
+
+// SupplierFilter berisi filter, paginasi, dan pengurutan untuk daftar supplier.
+type SupplierFilter struct {
+	ListOptions
+
+	Name string
+}
+
+// supplierSortFields adalah field yang boleh dipakai untuk mengurutkan supplier (true = numerik).
+var supplierSortFields = map[string]bool{
+	"id":   true,
+	"name": false,
+}
+
+// Validate memeriksa filter dan paginasi supplier. Pelanggaran memakai key nama parameter query.
+func (f *SupplierFilter) Validate() error {
+	var v validator
+	f.ListOptions.validate(&v, supplierSortFields)
+	return v.err()
+}
+
+// CursorFor membuat cursor yang menunjuk ke supplier s sesuai pengurutan filter.
+func (f *SupplierFilter) CursorFor(s Supplier) string {
+	var value interface{} = s.ID
+	if f.Sort == "name" {
+		value = s.Name
+	}
+	return EncodeCursor(Cursor{Sort: f.SortKey(), Value: value, ID: s.ID})
+}
+
+// PurchaseOrderFilter berisi filter, paginasi, dan pengurutan untuk daftar purchase order.
+// Status PurchaseOrderOpen mengambil purchase order yang masih menunggu barang; SupplierID 0 berarti semua supplier.
+type PurchaseOrderFilter struct {
+	ListOptions
+
+	Status     PurchaseOrderStatus
+	SupplierID int
+}
+
+// purchaseOrderSortFields adalah field yang boleh dipakai untuk mengurutkan purchase order (true = numerik).
+var purchaseOrderSortFields = map[string]bool{
+	"id": true,
+}
+
+// Validate memeriksa filter dan paginasi purchase order. Pelanggaran memakai key nama parameter query.
+func (f *PurchaseOrderFilter) Validate() error {
+	var v validator
+	f.ListOptions.validate(&v, purchaseOrderSortFields)
+	switch f.Status {
+	case "", PurchaseOrderOpen, PurchaseOrderOrdered, PurchaseOrderPartiallyReceived, PurchaseOrderReceived, PurchaseOrderCancelled:
+	default:
+		v.add("status", "must be one of open, ordered, partially_received, received, cancelled")
+	}
+	v.check(f.SupplierID >= 0, "supplier_id", "must be positive")
+	return v.err()
+}
+
+// Statuses mengembalikan status yang cocok dengan filter, atau nil jika semua status cocok.
+func (f *PurchaseOrderFilter) Statuses() []PurchaseOrderStatus {
+	switch f.Status {
+	case "":
+		return nil
+	case PurchaseOrderOpen:
+		return []PurchaseOrderStatus{PurchaseOrderOrdered, PurchaseOrderPartiallyReceived}
+	default:
+		return []PurchaseOrderStatus{f.Status}
+	}
+}
+
+// CursorFor membuat cursor yang menunjuk ke purchase order po.
+func (f *PurchaseOrderFilter) CursorFor(po PurchaseOrder) string {
+	return EncodeCursor(Cursor{Sort: f.SortKey(), Value: po.ID, ID: po.ID})
+}
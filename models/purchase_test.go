@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+// testPurchaseOrder membuat purchase order dengan dua baris, item 11 (10 unit) dan item 12 (4 unit),
+// yang sudah menerima received11 dan received12 unit.
+func testPurchaseOrder(received11, received12 int) PurchaseOrder {
+	return PurchaseOrder{ID: 1, Items: []PurchaseOrderItem{
+		{ID: 11, ProductID: 1, Quantity: 10, ReceivedQuantity: received11},
+		{ID: 12, ProductID: 2, Quantity: 4, ReceivedQuantity: received12},
+	}}
+}
+
+func TestPurchaseOrderReceiptQuantities(t *testing.T) {
+	tests := []struct {
+		name       string
+		order      PurchaseOrder
+		receipt    PurchaseOrderReceipt
+		want       map[int]int
+		wantFields map[string]string
+	}{
+		{
+			name:  "empty receipt receives everything remaining",
+			order: testPurchaseOrder(3, 0),
+			want:  map[int]int{0: 7, 1: 4},
+		},
+		{
+			name:  "empty receipt skips fully received items",
+			order: testPurchaseOrder(3, 4),
+			want:  map[int]int{0: 7},
+		},
+		{
+			name:    "partial receipt",
+			order:   testPurchaseOrder(0, 0),
+			receipt: PurchaseOrderReceipt{Items: []PurchaseOrderReceiptItem{{ItemID: 12, Quantity: 3}}},
+			want:    map[int]int{1: 3},
+		},
+		{
+			name:    "exactly the remaining quantity",
+			order:   testPurchaseOrder(6, 0),
+			receipt: PurchaseOrderReceipt{Items: []PurchaseOrderReceiptItem{{ItemID: 11, Quantity: 4}}},
+			want:    map[int]int{0: 4},
+		},
+		{
+			name:       "over-receipt is rejected",
+			order:      testPurchaseOrder(6, 0),
+			receipt:    PurchaseOrderReceipt{Items: []PurchaseOrderReceiptItem{{ItemID: 11, Quantity: 5}}},
+			wantFields: map[string]string{"items[0].quantity": "must not exceed the remaining quantity 4"},
+		},
+		{
+			name:  "unknown item is rejected",
+			order: testPurchaseOrder(0, 0),
+			receipt: PurchaseOrderReceipt{Items: []PurchaseOrderReceiptItem{
+				{ItemID: 11, Quantity: 1},
+				{ItemID: 99, Quantity: 1},
+			}},
+			wantFields: map[string]string{"items[1].item_id": "is not an item of this purchase order"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.order.ReceiptQuantities(tt.receipt)
+			if tt.wantFields != nil {
+				var validationErr *ValidationError
+				if !errors.As(err, &validationErr) || !maps.Equal(validationErr.Fields, tt.wantFields) {
+					t.Fatalf("error = %v, want fields %v", err, tt.wantFields)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("quantities = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPurchaseOrderReceivedStatus(t *testing.T) {
+	tests := []struct {
+		name  string
+		order PurchaseOrder
+		want  PurchaseOrderStatus
+	}{
+		{name: "nothing received", order: testPurchaseOrder(0, 0), want: PurchaseOrderOrdered},
+		{name: "one item partially received", order: testPurchaseOrder(3, 0), want: PurchaseOrderPartiallyReceived},
+		{name: "one item fully received", order: testPurchaseOrder(0, 4), want: PurchaseOrderPartiallyReceived},
+		{name: "everything received", order: testPurchaseOrder(10, 4), want: PurchaseOrderReceived},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.order.ReceivedStatus(); got != tt.want {
+				t.Errorf("ReceivedStatus() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Batas query pencarian produk (parameter q).
+const (
+	MaxSearchQueryLength = 100
+	MaxSearchTerms       = 8
+	DefaultSuggestLimit  = 10
+	MaxSuggestLimit      = 20
+)
+
+// ProductSuggestion adalah satu hasil autocomplete untuk kotak pencarian kasir.
+// Hanya berisi field yang dibutuhkan untuk menampilkan daftar saran.
+type ProductSuggestion struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	SKU      string `json:"sku,omitempty"`
+	Price    int    `json:"price"`
+	Stock    int    `json:"stock"`
+	Category string `json:"category,omitempty"`
+}
+
+// SuggestFilter berisi parameter autocomplete produk: query pencarian dan jumlah saran maksimal.
+type SuggestFilter struct {
+	Query string
+	Limit int
+}
+
+// Validate memeriksa parameter autocomplete. Pelanggaran memakai key nama parameter query.
+func (f *SuggestFilter) Validate() error {
+	var v validator
+	v.required(f.Query, "q", MaxSearchQueryLength)
+	v.check(strings.TrimSpace(f.Query) == "" || len(SearchTerms(f.Query)) > 0, "q", "must contain letters or digits")
+	v.check(f.Limit >= 1 && f.Limit <= MaxSuggestLimit, "limit", fmt.Sprintf("must be between 1 and %d", MaxSuggestLimit))
+	return v.err()
+}
+
+// SearchTerms memecah query pencarian menjadi kata berhuruf kecil yang hanya berisi huruf dan angka,
+// maksimal MaxSearchTerms kata. Tanda baca dan spasi dianggap pemisah.
+func SearchTerms(query string) []string {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) > MaxSearchTerms {
+		terms = terms[:MaxSearchTerms]
+	}
+	return terms
+}
+
+// SearchScore menghitung skor relevansi produk untuk query pencarian; 0 berarti produk tidak cocok.
+// SKU yang sama persis dengan query selalu berada di urutan teratas. Selain itu, setiap kata di query harus cocok
+// dengan salah satu kata di nama produk, nama kategori, atau SKU. Kecocokan persis bernilai paling tinggi,
+// diikuti awalan kata, potongan kata, dan kata dengan salah ketik; kecocokan di nama kategori bernilai setengahnya.
+// Nama yang lebih pendek (lebih sedikit kata di luar query) mendapat sedikit tambahan skor.
+// Dipakai oleh storage in-memory dan SQLite; PostgreSQL memakai tsvector dan pg_trgm dengan skala skor sendiri.
+func SearchScore(query, name, sku, category string) int {
+	terms := SearchTerms(query)
+	if len(terms) == 0 {
+		return 0
+	}
+	if sku != "" && strings.EqualFold(strings.TrimSpace(query), sku) {
+		return 1000
+	}
+	nameWords := SearchTerms(name)
+	categoryWords := SearchTerms(category)
+	skuWords := SearchTerms(sku)
+
+	score := 0
+	for _, term := range terms {
+		best := max(matchWords(term, nameWords), matchWords(term, categoryWords)/2)
+		for _, word := range skuWords {
+			if word == term {
+				best = max(best, 90)
+			} else if strings.HasPrefix(word, term) {
+				best = max(best, 70)
+			}
+		}
+		if best == 0 {
+			return 0
+		}
+		score += best
+	}
+
+	// Nama yang sama persis dengan query selalu berada di atas nama yang hanya mengandung kata query.
+	if strings.Join(nameWords, " ") == strings.Join(terms, " ") {
+		score += 100
+	}
+	return score + max(0, 10-max(0, len(nameWords)-len(terms)))
+}
+
+// matchWords mengembalikan nilai kecocokan terbaik kata term dengan salah satu kata di words.
+func matchWords(term string, words []string) int {
+	best := 0
+	for _, word := range words {
+		best = max(best, matchWord(term, word))
+	}
+	return best
+}
+
+// matchWord menilai kecocokan satu kata query dengan satu kata data.
+// Salah ketik ditoleransi untuk kata minimal 4 karakter: satu huruf salah, kurang, lebih, atau tertukar
+// (dua untuk kata minimal 8 karakter), baik terhadap seluruh kata maupun awalannya.
+func matchWord(term, word string) int {
+	switch {
+	case word == term:
+		return 100
+	case strings.HasPrefix(word, term):
+		return 80
+	case len([]rune(term)) >= 3 && strings.Contains(word, term):
+		return 60
+	}
+
+	t, w := []rune(term), []rune(word)
+	allowed := 0
+	switch {
+	case len(t) >= 8:
+		allowed = 2
+	case len(t) >= 4:
+		allowed = 1
+	default:
+		return 0
+	}
+	if editDistance(t, w) <= allowed {
+		return 40
+	}
+	if len(w) > len(t) && editDistance(t, w[:len(t)]) <= allowed {
+		return 30
+	}
+	return 0
+}
+
+// editDistance menghitung jarak edit antara a dan b (optimal string alignment):
+// jumlah minimal huruf yang disisipkan, dihapus, diganti, atau ditukar dengan huruf sebelahnya.
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,57 @@
+package models
+
+import "testing"
+
+func TestSearchScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		product  string
+		sku      string
+		category string
+		want     int
+	}{
+		{name: "exact name", query: "green tea", product: "Green Tea", category: "Drinks", want: 310},
+		{name: "exact sku", query: "TEA-01", product: "Green Tea", sku: "tea-01", want: 1000},
+		{name: "prefix", query: "cof", product: "Coffee Latte", category: "Drinks", want: 89},
+		{name: "one typo", query: "cofee", product: "Coffee", category: "Drinks", want: 50},
+		{name: "one typo in prefix", query: "cofe", product: "Coffee Latte", category: "Drinks", want: 39},
+		{name: "transposed letters", query: "cofefe", product: "Coffee", category: "Drinks", want: 50},
+		{name: "short term has no typo tolerance", query: "tee", product: "Tea", category: "Drinks", want: 0},
+		{name: "two typos in short term", query: "tofee", product: "Coffee", category: "Drinks", want: 0},
+		{name: "two typos in long term", query: "chocalete", product: "Chocolate", category: "Snacks", want: 50},
+		{name: "category name", query: "drinks", product: "Green Tea", category: "Drinks", want: 59},
+		{name: "every term must match", query: "green coffee", product: "Green Tea", category: "Drinks", want: 0},
+		{name: "query without letters", query: "--", product: "Green Tea", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SearchScore(tt.query, tt.product, tt.sku, tt.category); got != tt.want {
+				t.Errorf("SearchScore(%q, %q, %q, %q) = %d, want %d", tt.query, tt.product, tt.sku, tt.category, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"tea", "tea", 0},
+		{"", "tea", 3},
+		{"tea", "", 3},
+		{"cofee", "coffee", 1},
+		{"coffe", "coffee", 1},
+		{"cofefe", "coffee", 1},
+		{"tae", "tea", 1},
+		{"kitten", "sitting", 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
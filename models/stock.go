@@ -0,0 +1,109 @@
+package models
+
+import (
+	"slices"
+	"strconv"
+	"time"
+)
+
+// StockReason adalah alasan perubahan stok yang dicatat di ledger stock_movements.
+type StockReason string
+
+const (
+	// StockOpeningBalance adalah saldo awal untuk stok yang sudah ada sebelum ledger dibuat (migrasi 0010).
+	StockOpeningBalance StockReason = "opening_balance"
+	// StockInitial adalah stok awal saat produk atau varian dibuat.
+	StockInitial StockReason = "initial_stock"
+	// StockSale adalah pengurangan stok karena checkout; Reference berisi ID transaksi.
+	StockSale StockReason = "sale"
+	// StockImport adalah stok awal produk baru dari import CSV produk.
+	StockImport StockReason = "import"
+	// StockPurchaseReceipt adalah penerimaan barang dari purchase order; Reference berisi ID purchase order.
+	StockPurchaseReceipt StockReason = "purchase_receipt"
+	// StockOpname adalah koreksi stok dari posting sesi stock opname; Reference berisi ID sesi.
+	StockOpname StockReason = "stock_opname"
+
+	// Alasan penyesuaian stok lewat POST /api/product/{id}/stock-adjustments (lihat StockAdjustment).
+	StockRestock    StockReason = "restock"
+	StockDamage     StockReason = "damage"
+	StockCorrection StockReason = "correction"
+	StockTheft      StockReason = "theft"
+)
+
+// stockReasons adalah semua alasan yang dikenal, untuk memvalidasi filter riwayat stok.
+var stockReasons = []StockReason{
+	StockOpeningBalance, StockInitial, StockSale, StockImport, StockPurchaseReceipt, StockOpname,
+	StockRestock, StockDamage, StockCorrection, StockTheft,
+}
+
+// Valid melaporkan apakah r adalah alasan perubahan stok yang dikenal.
+func (r StockReason) Valid() bool {
+	return slices.Contains(stockReasons, r)
+}
+
+// StockMovement adalah satu baris ledger perubahan stok produk, atau stok varian jika VariantID diisi.
+// Delta positif menambah stok dan negatif mengurangi stok; StockAfter adalah stok setelah perubahan.
+// Reference merujuk dokumen penyebab perubahan, misalnya "transaction:12".
+type StockMovement struct {
+	ID         int         `json:"id"`
+	ProductID  int         `json:"product_id"`
+	VariantID  *int        `json:"variant_id,omitempty"`
+	Delta      int         `json:"delta"`
+	StockAfter int         `json:"stock_after"`
+	Reason     StockReason `json:"reason"`
+	Reference  string      `json:"reference,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// Reference membuat nilai StockMovement.Reference untuk dokumen kind dengan ID id, misalnya "transaction:12".
+func Reference(kind string, id int) string {
+	return kind + ":" + strconv.Itoa(id)
+}
+
+// StockAdjustment adalah request penyesuaian stok produk, atau stok varian jika VariantID diisi.
+// Delta ditambahkan ke stok saat ini (negatif mengurangi), sehingga tidak bentrok dengan checkout yang berjalan
+// bersamaan. Restock harus menambah stok, damage dan theft harus mengurangi, dan correction boleh keduanya.
+type StockAdjustment struct {
+	VariantID *int        `json:"variant_id"`
+	Delta     int         `json:"delta"`
+	Reason    StockReason `json:"reason"`
+}
+
+// StockMovementFilter berisi filter dan paginasi riwayat stok satu produk.
+// VariantID bernilai nil berarti semua pergerakan produk, termasuk pergerakan variannya.
+type StockMovementFilter struct {
+	ListOptions
+
+	ProductID int
+	VariantID *int
+	Reason    StockReason
+}
+
+// stockMovementSortFields adalah field yang boleh dipakai untuk mengurutkan riwayat stok (true = numerik).
+var stockMovementSortFields = map[string]bool{
+	"id": true,
+}
+
+// Validate memeriksa filter dan paginasi riwayat stok. Pelanggaran memakai key nama parameter query.
+func (f *StockMovementFilter) Validate() error {
+	var v validator
+	f.ListOptions.validate(&v, stockMovementSortFields)
+	if f.VariantID != nil {
+		v.check(*f.VariantID > 0, "variant_id", "must be positive")
+	}
+	v.check(f.Reason == "" || f.Reason.Valid(), "reason", "unknown stock reason")
+	return v.err()
+}
+
+// CursorFor membuat cursor yang menunjuk ke pergerakan stok m.
+func (f *StockMovementFilter) CursorFor(m StockMovement) string {
+	return EncodeCursor(Cursor{Sort: f.SortKey(), Value: m.ID, ID: m.ID})
+}
+
+// StockDiscrepancy adalah produk atau varian yang stoknya tidak sama dengan jumlah delta di ledger.
+type StockDiscrepancy struct {
+	ProductID   int  `json:"product_id"`
+	VariantID   *int `json:"variant_id,omitempty"`
+	Stock       int  `json:"stock"`
+	LedgerStock int  `json:"ledger_stock"`
+}
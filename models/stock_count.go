@@ -0,0 +1,188 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// StockCountStatus adalah status sesi stock opname.
+type StockCountStatus string
+
+const (
+	// StockCountOpen adalah sesi yang masih menerima hasil hitung.
+	StockCountOpen StockCountStatus = "open"
+	// StockCountPosted adalah sesi yang selisihnya sudah diterapkan ke stok.
+	StockCountPosted StockCountStatus = "posted"
+	// StockCountCancelled adalah sesi yang dibatalkan tanpa mengubah stok.
+	StockCountCancelled StockCountStatus = "cancelled"
+)
+
+// NewStockCountClosedError membuat ConflictError untuk sesi stock opname id yang sudah tidak open (status posted
+// atau cancelled), sehingga tidak bisa lagi menerima hasil hitung, diposting, atau dibatalkan.
+func NewStockCountClosedError(id int, status StockCountStatus) *ConflictError {
+	return &ConflictError{
+		Message: fmt.Sprintf("stock count %d is %s", id, status),
+		Details: map[string]interface{}{"status": status},
+	}
+}
+
+// NewStockCountOpenError membuat ConflictError untuk sesi stock opname baru saat sesi id masih open.
+// Hanya satu sesi yang boleh open agar selisih produk yang sama tidak diposting dua kali.
+func NewStockCountOpenError(id int) *ConflictError {
+	return &ConflictError{
+		Message: fmt.Sprintf("stock count %d is still open", id),
+		Details: map[string]interface{}{"stock_count_id": id},
+	}
+}
+
+// StockCount adalah sesi stock opname (penghitungan stok fisik). Saat sesi dibuka, stok setiap produk tanpa varian
+// dan setiap varian aktif (hanya kategori CategoryID jika diisi) disalin sebagai system_quantity barisnya.
+// Summary dan Items hanya diisi saat satu sesi dibaca, bukan pada daftar sesi.
+type StockCount struct {
+	ID         int                `json:"id"`
+	Status     StockCountStatus   `json:"status"`
+	CategoryID *int               `json:"category_id,omitempty"`
+	Note       string             `json:"note"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+	PostedAt   *time.Time         `json:"posted_at,omitempty"`
+	Summary    *StockCountSummary `json:"summary,omitempty"`
+	Items      []StockCountItem   `json:"items,omitempty"`
+}
+
+// StockCountItem adalah satu baris sesi stock opname untuk produk, atau varian produk jika VariantID diisi.
+// SystemQuantity adalah stok saat sesi dibuka dan CountedQuantity hasil hitung fisik (nil jika belum dihitung).
+// Variance adalah CountedQuantity - SystemQuantity, atau nil jika belum dihitung.
+type StockCountItem struct {
+	ID              int    `json:"id"`
+	ProductID       int    `json:"product_id"`
+	VariantID       *int   `json:"variant_id,omitempty"`
+	Name            string `json:"name"`
+	VariantName     string `json:"variant_name,omitempty"`
+	SKU             string `json:"sku,omitempty"`
+	SystemQuantity  int    `json:"system_quantity"`
+	CountedQuantity *int   `json:"counted_quantity"`
+	Variance        *int   `json:"variance"`
+}
+
+// StockCountSummary adalah ringkasan sesi stock opname. Surplus dan Shortage adalah jumlah unit yang lebih dan
+// kurang dari stok sistem, dari semua baris yang sudah dihitung.
+type StockCountSummary struct {
+	TotalItems    int `json:"total_items"`
+	CountedItems  int `json:"counted_items"`
+	VarianceItems int `json:"variance_items"`
+	Surplus       int `json:"surplus"`
+	Shortage      int `json:"shortage"`
+}
+
+// UpdateSummary menghitung ulang Variance setiap baris dan Summary dari Items.
+func (c *StockCount) UpdateSummary() {
+	summary := StockCountSummary{TotalItems: len(c.Items)}
+	for i := range c.Items {
+		item := &c.Items[i]
+		item.Variance = nil
+		if item.CountedQuantity == nil {
+			continue
+		}
+		variance := *item.CountedQuantity - item.SystemQuantity
+		item.Variance = &variance
+		summary.CountedItems++
+		switch {
+		case variance > 0:
+			summary.VarianceItems++
+			summary.Surplus += variance
+		case variance < 0:
+			summary.VarianceItems++
+			summary.Shortage -= variance
+		}
+	}
+	c.Summary = &summary
+}
+
+// StockCountVarianceReport adalah laporan selisih sesi stock opname: ringkasan sesi dan baris yang sudah dihitung
+// dengan hasil hitung berbeda dari stok sistem. Baris yang belum dihitung tidak diposting dan tidak dilaporkan.
+type StockCountVarianceReport struct {
+	StockCountID int               `json:"stock_count_id"`
+	Status       StockCountStatus  `json:"status"`
+	Summary      StockCountSummary `json:"summary"`
+	Items        []StockCountItem  `json:"items"`
+}
+
+// VarianceReport membuat laporan selisih dari Items.
+func (c *StockCount) VarianceReport() StockCountVarianceReport {
+	c.UpdateSummary()
+	report := StockCountVarianceReport{
+		StockCountID: c.ID,
+		Status:       c.Status,
+		Summary:      *c.Summary,
+		Items:        make([]StockCountItem, 0),
+	}
+	for _, item := range c.Items {
+		if item.Variance != nil && *item.Variance != 0 {
+			report.Items = append(report.Items, item)
+		}
+	}
+	return report
+}
+
+// StockCountEntry adalah hasil hitung fisik untuk satu baris sesi stock opname. Baris dipilih dengan ProductID
+// (dan VariantID untuk varian) atau dengan SKU produk atau varian.
+type StockCountEntry struct {
+	ProductID       int    `json:"product_id"`
+	VariantID       *int   `json:"variant_id"`
+	SKU             string `json:"sku"`
+	CountedQuantity *int   `json:"counted_quantity"`
+}
+
+// StockCountEntries adalah request pencatatan hasil hitung fisik. Hasil hitung baris yang sudah dihitung
+// sebelumnya ditimpa.
+type StockCountEntries struct {
+	Items []StockCountEntry `json:"items"`
+}
+
+// FindItem mengembalikan indeks baris Items yang dipilih entry, atau -1 jika tidak ada baris yang cocok.
+func (c *StockCount) FindItem(entry StockCountEntry) int {
+	for i, item := range c.Items {
+		if entry.SKU != "" {
+			if item.SKU == entry.SKU {
+				return i
+			}
+			continue
+		}
+		sameVariant := item.VariantID == nil && entry.VariantID == nil ||
+			item.VariantID != nil && entry.VariantID != nil && *item.VariantID == *entry.VariantID
+		if item.ProductID == entry.ProductID && sameVariant {
+			return i
+		}
+	}
+	return -1
+}
+
+// StockCountFilter berisi filter, paginasi, dan pengurutan untuk daftar sesi stock opname.
+type StockCountFilter struct {
+	ListOptions
+
+	Status StockCountStatus
+}
+
+// stockCountSortFields adalah field yang boleh dipakai untuk mengurutkan sesi stock opname (true = numerik).
+var stockCountSortFields = map[string]bool{
+	"id": true,
+}
+
+// Validate memeriksa filter dan paginasi sesi stock opname. Pelanggaran memakai key nama parameter query.
+func (f *StockCountFilter) Validate() error {
+	var v validator
+	f.ListOptions.validate(&v, stockCountSortFields)
+	switch f.Status {
+	case "", StockCountOpen, StockCountPosted, StockCountCancelled:
+	default:
+		v.add("status", "must be one of open, posted, cancelled")
+	}
+	return v.err()
+}
+
+// CursorFor membuat cursor yang menunjuk ke sesi stock opname c.
+func (f *StockCountFilter) CursorFor(c StockCount) string {
+	return EncodeCursor(Cursor{Sort: f.SortKey(), Value: c.ID, ID: c.ID})
+}
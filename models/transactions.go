@@ -7,20 +7,41 @@ type Transaction struct {
 	TotalAmount int                 `json:"total_amount"`
 	CreatedAt   time.Time           `json:"created_at"`
 	Details     []TransactionDetail `json:"details"`
+
+	// LowStock berisi produk atau varian yang stoknya turun ke batas pemesanan ulang karena transaksi ini.
+	// Hanya diisi saat checkout, untuk diteruskan ke notifier; tidak dikirim di response.
+	LowStock []LowStockEvent `json:"-"`
 }
 
+// TransactionDetail adalah satu baris transaksi.
+// VariantID dan VariantName terisi jika yang dibeli adalah varian produk.
 type TransactionDetail struct {
 	ID            int    `json:"id"`
 	TransactionID int    `json:"transaction_id"`
 	ProductID     int    `json:"product_id"`
 	ProductName   string `json:"product_name,omitempty"`
+	VariantID     *int   `json:"variant_id,omitempty"`
+	VariantName   string `json:"variant_name,omitempty"`
 	Quantity      int    `json:"quantity"`
 	Subtotal      int    `json:"subtotal"`
 }
 
+// CheckoutItem adalah satu item di keranjang checkout.
+// Produk dirujuk dengan ProductID atau dengan Barcode hasil scan kasir, tidak keduanya.
+// VariantID wajib diisi untuk produk yang punya varian, dan varian tersebut harus milik produk yang dirujuk.
 type CheckoutItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int    `json:"product_id,omitempty"`
+	Barcode   string `json:"barcode,omitempty"`
+	VariantID int    `json:"variant_id,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// ProductNotFound mengembalikan NotFoundError untuk produk yang dirujuk item, dengan ID atau barcode-nya.
+func (item CheckoutItem) ProductNotFound() error {
+	if item.Barcode != "" {
+		return &NotFoundError{Resource: "product with barcode", ID: item.Barcode}
+	}
+	return &NotFoundError{Resource: "product", ID: item.ProductID}
 }
 
 type CheckoutRequest struct {
@@ -0,0 +1,323 @@
+package models
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+// Batas panjang field teks, disesuaikan dengan ukuran kolom di database.
+const (
+	MaxCategoryNameLength        = 100
+	MaxCategoryDescriptionLength = 500
+	MaxProductNameLength         = 150
+	MaxProductSKULength          = 64
+	MaxCheckoutItems             = 100
+	MaxVariantOptions            = 5
+	MaxVariantOptionLength       = 50
+	MaxStockAdjustment           = 1000000
+	MaxSupplierNameLength        = 150
+	MaxSupplierContactLength     = 100
+	MaxSupplierPhoneLength       = 32
+	MaxSupplierEmailLength       = 254
+	MaxPurchaseOrderItems        = 100
+	MaxPurchaseOrderNoteLength   = 500
+	MaxStockCountNoteLength      = 500
+)
+
+// validator mengumpulkan semua pelanggaran validasi sebelum dikembalikan sekaligus.
+// Hanya pesan pertama untuk setiap field yang disimpan.
+type validator struct {
+	fields map[string]string
+}
+
+// add mencatat pesan kesalahan untuk field jika field tersebut belum punya pesan.
+func (v *validator) add(field, message string) {
+	if v.fields == nil {
+		v.fields = make(map[string]string)
+	}
+	if _, exists := v.fields[field]; !exists {
+		v.fields[field] = message
+	}
+}
+
+// check mencatat pesan kesalahan jika ok bernilai false.
+func (v *validator) check(ok bool, field, message string) {
+	if !ok {
+		v.add(field, message)
+	}
+}
+
+// required memastikan string tidak kosong (setelah spasi di awal dan akhir dibuang)
+// dan panjangnya tidak melebihi max karakter.
+func (v *validator) required(value, field string, max int) {
+	if strings.TrimSpace(value) == "" {
+		v.add(field, "is required")
+		return
+	}
+	v.maxLength(value, field, max)
+}
+
+// maxLength memastikan panjang string (dalam karakter, bukan byte) tidak melebihi max.
+func (v *validator) maxLength(value, field string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.add(field, fmt.Sprintf("must be at most %d characters", max))
+	}
+}
+
+// sku memastikan SKU yang diisi hanya berisi huruf, angka, '-', '_', atau '.' dan tidak terlalu panjang.
+// SKU kosong berarti produk tidak punya SKU.
+func (v *validator) sku(value, field string) {
+	v.maxLength(value, field, MaxProductSKULength)
+	for _, r := range value {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			v.add(field, "may only contain letters, digits, '-', '_' and '.'")
+			return
+		}
+	}
+}
+
+// barcode memastikan barcode yang diisi adalah EAN-13 atau UPC-A dengan check digit yang benar.
+// Barcode kosong berarti produk tidak punya barcode.
+func (v *validator) barcode(value, field string) {
+	v.check(value == "" || ValidBarcode(value), field, "must be a valid EAN-13 or UPC-A barcode")
+}
+
+// err mengembalikan *ValidationError berisi semua pelanggaran, atau nil jika tidak ada.
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// Validate memeriksa field wajib dan batas panjang kategori.
+// Semua pelanggaran dikembalikan sekaligus sebagai *ValidationError dengan key nama field JSON.
+func (c *Category) Validate() error {
+	var v validator
+	v.required(c.Name, "name", MaxCategoryNameLength)
+	v.maxLength(c.Description, "description", MaxCategoryDescriptionLength)
+	return v.err()
+}
+
+// Validate memeriksa mode penghapusan kategori categoryID.
+// Mode kosong dianggap restrict; mode reassign wajib punya kategori tujuan yang berbeda dari kategori yang dihapus.
+func (p *CategoryDeletePolicy) Validate(categoryID int) error {
+	if p.Mode == "" {
+		p.Mode = CategoryDeleteRestrict
+	}
+
+	var v validator
+	switch p.Mode {
+	case CategoryDeleteRestrict, CategoryDeleteCascade:
+		v.check(p.TargetCategoryID == 0, "target_category_id", "is only allowed with mode reassign")
+	case CategoryDeleteReassign:
+		v.check(p.TargetCategoryID > 0, "target_category_id", "is required with mode reassign")
+		v.check(p.TargetCategoryID != categoryID, "target_category_id", "must differ from the deleted category")
+	default:
+		v.add("mode", "must be one of restrict, reassign, cascade")
+	}
+	return v.err()
+}
+
+// Validate memeriksa field wajib, batas panjang, serta harga dan stok yang tidak boleh negatif.
+// Keberadaan kategori tidak diperiksa di sini karena membutuhkan akses ke database.
+func (p *Product) Validate() error {
+	var v validator
+	v.required(p.Name, "name", MaxProductNameLength)
+	v.check(p.Price >= 0, "price", "must not be negative")
+	v.check(p.Stock >= 0, "stock", "must not be negative")
+	v.check(p.CategoryID != 0, "category_id", "is required")
+	v.check(p.CategoryID >= 0, "category_id", "must be positive")
+	v.sku(p.SKU, "sku")
+	v.barcode(p.Barcode, "barcode")
+	v.check(p.ReorderLevel >= 0, "reorder_level", "must not be negative")
+	v.check(p.ReorderQty >= 0, "reorder_qty", "must not be negative")
+	return v.err()
+}
+
+// Validate memeriksa delta dan alasan penyesuaian stok. Delta tidak boleh 0 atau melebihi MaxStockAdjustment,
+// dan tandanya harus sesuai alasan: restock menambah stok, damage dan theft mengurangi stok.
+func (a *StockAdjustment) Validate() error {
+	var v validator
+	v.check(a.Delta != 0, "delta", "must not be zero")
+	v.check(a.Delta >= -MaxStockAdjustment && a.Delta <= MaxStockAdjustment, "delta",
+		fmt.Sprintf("must be between -%d and %d", MaxStockAdjustment, MaxStockAdjustment))
+	switch a.Reason {
+	case StockRestock:
+		v.check(a.Delta > 0, "delta", "must be positive for restock")
+	case StockDamage, StockTheft:
+		v.check(a.Delta < 0, "delta", "must be negative for "+string(a.Reason))
+	case StockCorrection:
+	case "":
+		v.add("reason", "is required")
+	default:
+		v.add("reason", "must be one of restock, damage, correction, theft")
+	}
+	if a.VariantID != nil {
+		v.check(*a.VariantID > 0, "variant_id", "must be positive")
+	}
+	return v.err()
+}
+
+// Validate memeriksa opsi, SKU, harga, dan stok varian.
+// Opsi harus berisi 1 sampai MaxVariantOptions sumbu dengan nama dan nilai yang tidak kosong;
+// panggil NormalizeOptions terlebih dahulu agar spasi di awal dan akhir tidak dihitung.
+func (v *ProductVariant) Validate() error {
+	var val validator
+	val.check(len(v.Options) > 0, "options", "must contain at least one option")
+	val.check(len(v.Options) <= MaxVariantOptions, "options", fmt.Sprintf("must contain at most %d options", MaxVariantOptions))
+	for _, name := range v.OptionNames() {
+		val.check(name != "", "options", "option names must not be empty")
+		val.maxLength(name, "options", MaxVariantOptionLength)
+		val.required(v.Options[name], "options."+name, MaxVariantOptionLength)
+	}
+	val.sku(v.SKU, "sku")
+	val.check(v.Price == nil || *v.Price >= 0, "price", "must not be negative")
+	val.check(v.Stock >= 0, "stock", "must not be negative")
+	return val.err()
+}
+
+// Validate memeriksa bahwa checkout berisi minimal satu item, dan setiap item
+// merujuk produk (dengan product_id atau barcode) serta punya quantity yang positif.
+// variant_id bersifat opsional, tetapi jika diisi harus positif.
+// Pelanggaran pada item memakai key seperti "items[0].quantity".
+func (r *CheckoutRequest) Validate() error {
+	var v validator
+	v.check(len(r.Items) > 0, "items", "must contain at least one item")
+	v.check(len(r.Items) <= MaxCheckoutItems, "items", fmt.Sprintf("must contain at most %d items", MaxCheckoutItems))
+
+	for i, item := range r.Items {
+		prefix := fmt.Sprintf("items[%d].", i)
+		if item.Barcode != "" {
+			v.check(item.ProductID == 0, prefix+"barcode", "must not be combined with product_id")
+			v.barcode(item.Barcode, prefix+"barcode")
+		} else {
+			v.check(item.ProductID != 0, prefix+"product_id", "is required unless barcode is set")
+			v.check(item.ProductID > 0, prefix+"product_id", "must be positive")
+		}
+		v.check(item.VariantID >= 0, prefix+"variant_id", "must be positive")
+		v.check(item.Quantity > 0, prefix+"quantity", "must be positive")
+	}
+	return v.err()
+}
+
+// Validate memeriksa field wajib dan batas panjang supplier. Email boleh kosong, tetapi jika diisi harus berupa
+// satu alamat email tanpa nama tampilan.
+func (s *Supplier) Validate() error {
+	var v validator
+	v.required(s.Name, "name", MaxSupplierNameLength)
+	v.maxLength(s.ContactName, "contact_name", MaxSupplierContactLength)
+	v.maxLength(s.Phone, "phone", MaxSupplierPhoneLength)
+	v.maxLength(s.Email, "email", MaxSupplierEmailLength)
+	if s.Email != "" {
+		addr, err := mail.ParseAddress(s.Email)
+		v.check(err == nil && addr.Address == s.Email, "email", "must be a valid email address")
+	}
+	return v.err()
+}
+
+// Validate memeriksa supplier, catatan, dan baris purchase order. Setiap baris harus merujuk produk dengan
+// product_id positif, quantity antara 1 dan MaxStockAdjustment, dan unit_cost yang tidak negatif; produk atau varian
+// yang sama tidak boleh muncul di dua baris. Keberadaan supplier, produk, dan varian diperiksa oleh service.
+// Pelanggaran pada baris memakai key seperti "items[0].quantity".
+func (po *PurchaseOrder) Validate() error {
+	var v validator
+	v.check(po.SupplierID != 0, "supplier_id", "is required")
+	v.check(po.SupplierID >= 0, "supplier_id", "must be positive")
+	v.maxLength(po.Note, "note", MaxPurchaseOrderNoteLength)
+	v.check(len(po.Items) > 0, "items", "must contain at least one item")
+	v.check(len(po.Items) <= MaxPurchaseOrderItems, "items", fmt.Sprintf("must contain at most %d items", MaxPurchaseOrderItems))
+
+	type line struct{ productID, variantID int }
+	seen := make(map[line]bool)
+	for i, item := range po.Items {
+		prefix := fmt.Sprintf("items[%d].", i)
+		v.check(item.ProductID != 0, prefix+"product_id", "is required")
+		v.check(item.ProductID >= 0, prefix+"product_id", "must be positive")
+		variantID := 0
+		if item.VariantID != nil {
+			variantID = *item.VariantID
+			v.check(variantID > 0, prefix+"variant_id", "must be positive")
+		}
+		v.check(item.Quantity > 0 && item.Quantity <= MaxStockAdjustment, prefix+"quantity",
+			fmt.Sprintf("must be between 1 and %d", MaxStockAdjustment))
+		v.check(item.UnitCost >= 0, prefix+"unit_cost", "must not be negative")
+
+		key := line{item.ProductID, variantID}
+		v.check(!seen[key], prefix+"product_id", "is listed more than once")
+		seen[key] = true
+	}
+	return v.err()
+}
+
+// Validate memeriksa baris penerimaan barang. Items boleh kosong (terima semua sisa barang); jika diisi, setiap
+// baris harus merujuk item_id positif yang berbeda dengan quantity antara 1 dan MaxStockAdjustment.
+// Apakah quantity melebihi sisa barang diperiksa oleh repository saat barang diterima.
+func (r *PurchaseOrderReceipt) Validate() error {
+	var v validator
+	v.check(len(r.Items) <= MaxPurchaseOrderItems, "items", fmt.Sprintf("must contain at most %d items", MaxPurchaseOrderItems))
+
+	seen := make(map[int]bool)
+	for i, item := range r.Items {
+		prefix := fmt.Sprintf("items[%d].", i)
+		v.check(item.ItemID != 0, prefix+"item_id", "is required")
+		v.check(item.ItemID >= 0, prefix+"item_id", "must be positive")
+		v.check(item.Quantity > 0 && item.Quantity <= MaxStockAdjustment, prefix+"quantity",
+			fmt.Sprintf("must be between 1 and %d", MaxStockAdjustment))
+		v.check(!seen[item.ItemID], prefix+"item_id", "is listed more than once")
+		seen[item.ItemID] = true
+	}
+	return v.err()
+}
+
+// Validate memeriksa catatan dan kategori sesi stock opname baru. Keberadaan kategori diperiksa oleh service.
+func (c *StockCount) Validate() error {
+	var v validator
+	v.maxLength(c.Note, "note", MaxStockCountNoteLength)
+	if c.CategoryID != nil {
+		v.check(*c.CategoryID > 0, "category_id", "must be positive")
+	}
+	return v.err()
+}
+
+// Validate memeriksa hasil hitung fisik: 1 sampai MaxImportRows baris, masing-masing valid menurut
+// StockCountEntry.Validate dengan key seperti "items[0].counted_quantity".
+// Apakah baris ada di sesi stock opname diperiksa oleh service.
+func (e *StockCountEntries) Validate() error {
+	var v validator
+	v.check(len(e.Items) > 0, "items", "must contain at least one item")
+	v.check(len(e.Items) <= MaxImportRows, "items", fmt.Sprintf("must contain at most %d items", MaxImportRows))
+	for i := range e.Items {
+		e.Items[i].validate(&v, fmt.Sprintf("items[%d].", i))
+	}
+	return v.err()
+}
+
+// Validate memeriksa satu hasil hitung fisik. Baris dipilih dengan sku atau dengan product_id (dan variant_id),
+// tidak keduanya, dan counted_quantity wajib diisi antara 0 dan MaxStockAdjustment.
+func (e *StockCountEntry) Validate() error {
+	var v validator
+	e.validate(&v, "")
+	return v.err()
+}
+
+// validate mencatat pelanggaran StockCountEntry.Validate dengan key berawalan prefix.
+func (e *StockCountEntry) validate(v *validator, prefix string) {
+	if e.SKU != "" {
+		v.check(e.ProductID == 0 && e.VariantID == nil, prefix+"sku", "must not be combined with product_id or variant_id")
+	} else {
+		v.check(e.ProductID != 0, prefix+"product_id", "is required when sku is empty")
+		v.check(e.ProductID >= 0, prefix+"product_id", "must be positive")
+	}
+	if e.VariantID != nil {
+		v.check(*e.VariantID > 0, prefix+"variant_id", "must be positive")
+	}
+	if e.CountedQuantity == nil {
+		v.add(prefix+"counted_quantity", "is required")
+	} else {
+		v.check(*e.CountedQuantity >= 0 && *e.CountedQuantity <= MaxStockAdjustment, prefix+"counted_quantity",
+			fmt.Sprintf("must be between 0 and %d", MaxStockAdjustment))
+	}
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ProductVariant adalah varian produk, misalnya kaos ukuran M warna merah.
+// Options berisi nilai untuk setiap sumbu opsi (misalnya {"size": "M", "color": "red"});
+// semua varian aktif dari satu produk memakai nama sumbu yang sama.
+// Price bernilai nil berarti varian memakai harga produk. Stock varian berdiri sendiri dan
+// dikurangi saat checkout, terpisah dari stok produk.
+type ProductVariant struct {
+	ID        int               `json:"id"`
+	ProductID int               `json:"product_id"`
+	SKU       string            `json:"sku,omitempty"`
+	Options   map[string]string `json:"options"`
+	Price     *int              `json:"price,omitempty"`
+	Stock     int               `json:"stock"`
+	DeletedAt *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// NormalizeOptions merapikan nama sumbu opsi (huruf kecil, tanpa spasi di awal dan akhir)
+// dan nilainya (tanpa spasi di awal dan akhir), sehingga "Size" dan "size " dianggap sumbu yang sama.
+func (v *ProductVariant) NormalizeOptions() {
+	options := make(map[string]string, len(v.Options))
+	for name, value := range v.Options {
+		options[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+	v.Options = options
+}
+
+// EffectivePrice mengembalikan harga varian, atau productPrice jika varian tidak punya harga sendiri.
+func (v *ProductVariant) EffectivePrice(productPrice int) int {
+	if v.Price != nil {
+		return *v.Price
+	}
+	return productPrice
+}
+
+// OptionNames mengembalikan nama sumbu opsi varian secara terurut.
+func (v *ProductVariant) OptionNames() []string {
+	names := make([]string, 0, len(v.Options))
+	for name := range v.Options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Label mengembalikan nilai opsi varian sesuai urutan nama sumbu, misalnya "red / M".
+func (v *ProductVariant) Label() string {
+	names := v.OptionNames()
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = v.Options[name]
+	}
+	return strings.Join(values, " / ")
+}
+
+// SameOptions mengembalikan true jika kedua varian punya kombinasi opsi yang sama persis.
+func (v *ProductVariant) SameOptions(other *ProductVariant) bool {
+	if len(v.Options) != len(other.Options) {
+		return false
+	}
+	for name, value := range v.Options {
+		if otherValue, ok := other.Options[name]; !ok || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// SameAxes mengembalikan true jika kedua varian memakai nama sumbu opsi yang sama.
+func (v *ProductVariant) SameAxes(other *ProductVariant) bool {
+	return slices.Equal(v.OptionNames(), other.OptionNames())
+}
@@ -0,0 +1,36 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+	"task-session-1/models"
+)
+
+// LogNotifier menulis setiap peringatan stok menipis sebagai satu baris log level warn.
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLogNotifier adalah konstruktor untuk membuat instance LogNotifier yang memakai logger default.
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{logger: slog.Default()}
+}
+
+// NotifyLowStock menulis peringatan ke log. Tidak pernah gagal.
+func (n *LogNotifier) NotifyLowStock(ctx context.Context, events []models.LowStockEvent) error {
+	for _, e := range events {
+		attrs := []interface{}{
+			"product_id", e.ProductID,
+			"name", e.Name,
+			"stock", e.Stock,
+			"reorder_level", e.ReorderLevel,
+			"reorder_qty", e.ReorderQty,
+			"transaction_id", e.TransactionID,
+		}
+		if e.VariantID != nil {
+			attrs = append(attrs, "variant_id", *e.VariantID, "variant_name", e.VariantName)
+		}
+		n.logger.WarnContext(ctx, "low stock", attrs...)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// Package notify mengirim peringatan operasional, seperti stok menipis, ke tujuan yang bisa diganti:
+// log aplikasi, webhook HTTP, atau tidak ke mana pun.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"task-session-1/models"
+)
+
+// Nilai yang valid untuk LOW_STOCK_NOTIFIER.
+const (
+	KindLog     = "log"
+	KindWebhook = "webhook"
+	KindNone    = "none"
+)
+
+// Notifier mengirim peringatan stok menipis. Implementasi harus aman dipakai dari banyak goroutine.
+type Notifier interface {
+	// NotifyLowStock mengirim semua peringatan dari satu transaksi sekaligus.
+	NotifyLowStock(ctx context.Context, events []models.LowStockEvent) error
+}
+
+// New membuat Notifier sesuai kind: KindLog, KindWebhook (mengirim ke webhookURL), atau KindNone.
+func New(kind, webhookURL string) (Notifier, error) {
+	switch kind {
+	case KindLog:
+		return NewLogNotifier(), nil
+	case KindWebhook:
+		return NewWebhookNotifier(webhookURL), nil
+	case KindNone:
+		return Nop{}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", kind)
+	}
+}
+
+// Nop adalah Notifier yang mengabaikan semua peringatan.
+type Nop struct{}
+
+// NotifyLowStock tidak melakukan apa pun.
+func (Nop) NotifyLowStock(context.Context, []models.LowStockEvent) error {
+	return nil
+}
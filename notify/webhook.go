@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"task-session-1/models"
+	"time"
+)
+
+// webhookTimeout adalah batas waktu satu request webhook, termasuk membaca response.
+const webhookTimeout = 10 * time.Second
+
+// WebhookNotifier mengirim peringatan stok menipis sebagai POST JSON ke URL webhook:
+//
+//	{"event": "low_stock", "items": [{"product_id": 1, "stock": 3, "reorder_level": 5, ...}]}
+//
+// Response selain 2xx dianggap gagal. Tidak ada retry; peringatan yang gagal hanya dicatat di log oleh pemanggil.
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier adalah konstruktor untuk membuat instance WebhookNotifier yang mengirim ke url.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: webhookTimeout}}
+}
+
+// webhookPayload adalah body JSON yang dikirim ke webhook.
+type webhookPayload struct {
+	Event string                 `json:"event"`
+	Items []models.LowStockEvent `json:"items"`
+}
+
+// NotifyLowStock mengirim semua peringatan dalam satu request.
+func (n *WebhookNotifier) NotifyLowStock(ctx context.Context, events []models.LowStockEvent) error {
+	body, err := json.Marshal(webhookPayload{Event: "low_stock", Items: events})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send low stock webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("send low stock webhook: unexpected status %s", resp.Status)
+	}
+	return nil
+}
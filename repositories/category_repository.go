@@ -1,75 +1,131 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"errors"
+	"strings"
+	"task-session-1/database"
 	"task-session-1/models"
 )
 
 // CategoryRepository adalah struct yang menyimpan koneksi database untuk operasi kategori.
-// db adalah pointer ke koneksi database PostgreSQL.
+// db adalah koneksi database (PostgreSQL atau SQLite) beserta dialect-nya.
 type CategoryRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 // NewCategoryRepository adalah konstruktor untuk membuat instance CategoryRepository.
 // Menerima koneksi database sebagai parameter dan mengembalikan pointer ke CategoryRepository.
-func NewCategoryRepository(db *sql.DB) *CategoryRepository {
+func NewCategoryRepository(db *database.DB) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
-// GetAll mengambil semua data kategori dari database.
-// Query SQL mengambil semua kolom dari tabel category.
-// Mengembalikan slice dari Category dan error jika ada.
-func (repo *CategoryRepository) GetAll() ([]models.Category, error) {
-	// Query SQL untuk mengambil semua kategori.
-	query := "SELECT id, name, description FROM category"
+// categoryColumns adalah kolom SELECT untuk kategori (alias tabel c) beserta jumlah produk aktifnya.
+// Jumlah produk dihitung dengan subquery per baris yang memakai index product(category_id),
+// sehingga hanya kategori di halaman yang diminta yang dihitung.
+const categoryColumns = "c.id, c.name, c.description, " +
+	"(SELECT COUNT(*) FROM product cp WHERE cp.category_id = c.id AND cp.deleted_at IS NULL), c.deleted_at"
+
+// categoryDest mengembalikan tujuan Scan untuk kolom dari categoryColumns, sesuai urutannya.
+func categoryDest(c *models.Category) []interface{} {
+	return []interface{}{&c.ID, &c.Name, &c.Description, &c.ProductCount, &c.DeletedAt}
+}
+
+// categorySortColumns memetakan field pengurutan ke kolom tabel category.
+var categorySortColumns = map[string]string{
+	"id":   "c.id",
+	"name": "c.name",
+}
+
+// GetAll mengambil satu halaman kategori dari database beserta jumlah produk setiap kategori.
+// Kategori yang sudah dihapus tidak diambil kecuali filter.IncludeDeleted aktif.
+// Jika filter.Name tidak kosong, hanya kategori yang namanya mengandung nilai tersebut yang diambil.
+// Mengembalikan Page berisi kategori, cursor halaman berikutnya, dan total kategori yang cocok dengan filter.
+func (repo *CategoryRepository) GetAll(ctx context.Context, filter models.CategoryFilter) (*models.Page[models.Category], error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var q queryBuilder
+	if !filter.IncludeDeleted {
+		q.where("c.deleted_at IS NULL")
+	}
+	if filter.Name != "" {
+		q.where("c.name " + repo.db.Dialect.ILike() + " " + q.arg("%"+filter.Name+"%"))
+	}
+
+	// Menghitung total kategori yang cocok dengan filter.
+	page := &models.Page[models.Category]{Items: make([]models.Category, 0)}
+	countQuery := "SELECT COUNT(*) FROM category c" + q.whereClause()
+	err := repo.db.QueryRowContext(ctx, repo.db.Rebind(countQuery), q.args...).Scan(&page.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query SQL untuk mengambil satu halaman kategori.
+	column := categorySortColumns[filter.Sort]
+	q.keyset(column, "c.id", filter.ListOptions)
+	query := "SELECT " + categoryColumns + " FROM category c" + q.whereClause() + q.orderLimit(column, "c.id", filter.ListOptions)
 	// Menjalankan query dan mendapatkan rows.
-	rows, err := repo.db.Query(query)
+	rows, err := repo.db.QueryContext(ctx, repo.db.Rebind(query), q.args...)
 	if err != nil {
 		return nil, err
 	}
 	// Pastikan rows ditutup setelah selesai.
 	defer rows.Close()
 
-	// Membuat slice kosong untuk menyimpan hasil.
-	category := make([]models.Category, 0)
 	// Iterasi setiap row hasil query.
 	for rows.Next() {
 		var p models.Category
 		// Scan data dari row ke struct Category.
-		err := rows.Scan(&p.ID, &p.Name, &p.Description)
+		err := rows.Scan(categoryDest(&p)...)
 		if err != nil {
 			return nil, err
 		}
-		// Tambahkan kategori ke slice.
-		category = append(category, p)
+		// Tambahkan kategori ke halaman.
+		page.Items = append(page.Items, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
 	}
 
-	return category, nil
+	// Query mengambil satu data lebih banyak dari limit; jika ada, berarti masih ada halaman berikutnya.
+	if len(page.Items) > filter.Limit {
+		page.Items = page.Items[:filter.Limit]
+		page.NextCursor = filter.CursorFor(page.Items[filter.Limit-1])
+	}
+	return page, nil
 }
 
 // Create menyisipkan kategori baru ke database.
 // Menggunakan INSERT dengan RETURNING id untuk mendapatkan ID yang dihasilkan.
 // Mengembalikan error jika penyisipan gagal.
-func (repo *CategoryRepository) Create(category *models.Category) error {
+func (repo *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
 	// Query INSERT untuk menyisipkan kategori baru.
 	query := "INSERT INTO category (name, description) VALUES ($1, $2) RETURNING id"
 	// Menjalankan query dan scan ID yang dihasilkan ke category.ID.
-	err := repo.db.QueryRow(query, category.Name, category.Description).Scan(&category.ID)
+	err := repo.db.QueryRowContext(ctx, repo.db.Rebind(query), category.Name, category.Description).Scan(&category.ID)
 	return err
 }
 
-// GetByID mengambil satu kategori berdasarkan ID.
-// Jika kategori tidak ditemukan, mengembalikan nil tanpa error.
+// GetByID mengambil satu kategori berdasarkan ID beserta jumlah produknya.
+// Jika kategori tidak ditemukan (atau sudah dihapus dan opts.IncludeDeleted tidak aktif), mengembalikan nil tanpa error.
 // Mengembalikan pointer ke Category dan error jika ada.
-func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
+func (repo *CategoryRepository) GetByID(ctx context.Context, id int, opts models.CategoryOptions) (*models.Category, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
 	// Query SELECT untuk mengambil kategori berdasarkan ID.
-	query := "SELECT id, name, description FROM category WHERE id = $1"
+	query := "SELECT " + categoryColumns + " FROM category c WHERE c.id = $1"
+	if !opts.IncludeDeleted {
+		query += " AND c.deleted_at IS NULL"
+	}
 
 	var p models.Category
 	// Menjalankan query dan scan hasil ke struct Category.
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Description)
+	err := repo.db.QueryRowContext(ctx, repo.db.Rebind(query), id).Scan(categoryDest(&p)...)
 	// Jika tidak ada row ditemukan, kembalikan nil.
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -86,11 +142,14 @@ func (repo *CategoryRepository) GetByID(id int) (*models.Category, error) {
 // Menggunakan UPDATE dan memeriksa apakah ada baris yang terpengaruh.
 // Jika tidak ada baris yang terpengaruh, berarti kategori tidak ditemukan.
 // Mengembalikan error jika update gagal.
-func (repo *CategoryRepository) Update(category *models.Category) error {
+func (repo *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
 	// Query UPDATE untuk memperbarui kategori.
-	query := "UPDATE category SET name = $1, description = $2 WHERE id = $3"
+	query := "UPDATE category SET name = $1, description = $2 WHERE id = $3 AND deleted_at IS NULL"
 	// Menjalankan query dan mendapatkan result.
-	result, err := repo.db.Exec(query, category.Name, category.Description, category.ID)
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), category.Name, category.Description, category.ID)
 	if err != nil {
 		return err
 	}
@@ -103,34 +162,150 @@ func (repo *CategoryRepository) Update(category *models.Category) error {
 
 	// Jika tidak ada baris yang terpengaruh, kategori tidak ditemukan.
 	if rows == 0 {
-		return errors.New("Category not found!")
+		return &models.NotFoundError{Resource: "category", ID: category.ID}
 	}
 
 	return nil
 }
 
-// Delete menghapus kategori berdasarkan ID.
-// Menggunakan DELETE dan memeriksa apakah ada baris yang terpengaruh.
-// Jika tidak ada baris yang terpengaruh, berarti kategori tidak ditemukan.
-// Mengembalikan error jika delete gagal.
-func (repo *CategoryRepository) Delete(id int) error {
-	// Query DELETE untuk menghapus kategori.
-	query := "DELETE FROM category WHERE id = $1"
-	// Menjalankan query dan mendapatkan result.
-	result, err := repo.db.Exec(query, id)
+// Patch memperbarui hanya kolom kategori yang diisi di patch dengan satu query UPDATE dinamis.
+// Mengembalikan NotFoundError jika kategori tidak ditemukan.
+func (repo *CategoryRepository) Patch(ctx context.Context, id int, patch models.CategoryPatch) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var q queryBuilder
+	var sets []string
+	if patch.Name != nil {
+		sets = append(sets, "name = "+q.arg(*patch.Name))
+	}
+	if patch.Description != nil {
+		sets = append(sets, "description = "+q.arg(*patch.Description))
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	query := "UPDATE category SET " + strings.Join(sets, ", ") + " WHERE id = " + q.arg(id) + " AND deleted_at IS NULL"
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), q.args...)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+
+	if rows == 0 {
+		return &models.NotFoundError{Resource: "category", ID: id}
+	}
+
+	return nil
+}
+
+// Delete menghapus kategori berdasarkan ID secara soft delete sesuai policy, dalam satu transaksi:
+//   - restrict: ConflictError berisi product_count jika kategori masih punya produk aktif.
+//   - reassign: semua produk kategori (termasuk yang sudah dihapus) dipindahkan ke TargetCategoryID;
+//     ValidationError jika kategori tujuan tidak ada atau sudah dihapus.
+//   - cascade: semua produk aktif di kategori ikut di-soft delete.
+//
+// Mengembalikan jumlah produk yang dipindahkan atau ikut dihapus,
+// atau NotFoundError jika kategori tidak ditemukan atau sudah dihapus.
+func (repo *CategoryRepository) Delete(ctx context.Context, id int, policy models.CategoryDeletePolicy) (int, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// Query UPDATE untuk menandai kategori sebagai dihapus. Jika policy gagal, transaksi di-rollback.
+	query := "UPDATE category SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
+	result, err := tx.ExecContext(ctx, repo.db.Rebind(query), id)
+	if err != nil {
+		return 0, err
+	}
 	// Memeriksa jumlah baris yang terpengaruh.
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	// Jika tidak ada baris yang terpengaruh, kategori tidak ditemukan atau sudah dihapus.
+	if rows == 0 {
+		return 0, &models.NotFoundError{Resource: "category", ID: id}
+	}
+
+	var affected int64
+	switch policy.Mode {
+	case models.CategoryDeleteReassign:
+		// Kategori tujuan harus ada dan belum dihapus.
+		var target int
+		err = tx.QueryRowContext(ctx,
+			repo.db.Rebind("SELECT COUNT(*) FROM category WHERE id = $1 AND deleted_at IS NULL"), policy.TargetCategoryID,
+		).Scan(&target)
+		if err != nil {
+			return 0, err
+		}
+		if target == 0 {
+			return 0, models.NewValidationError("target_category_id", "category not found")
+		}
+
+		query = "UPDATE product SET category_id = $1 WHERE category_id = $2"
+		result, err = tx.ExecContext(ctx, repo.db.Rebind(query), policy.TargetCategoryID, id)
+	case models.CategoryDeleteCascade:
+		query = "UPDATE product SET deleted_at = CURRENT_TIMESTAMP WHERE category_id = $1 AND deleted_at IS NULL"
+		result, err = tx.ExecContext(ctx, repo.db.Rebind(query), id)
+	default:
+		// Menghitung produk aktif yang masih memakai kategori ini.
+		var active int
+		err = tx.QueryRowContext(ctx,
+			repo.db.Rebind("SELECT COUNT(*) FROM product WHERE category_id = $1 AND deleted_at IS NULL"), id,
+		).Scan(&active)
+		if err != nil {
+			return 0, err
+		}
+		if active > 0 {
+			return 0, &models.ConflictError{
+				Message: "category is still referenced by products",
+				Details: map[string]interface{}{"product_count": active},
+			}
+		}
+		return 0, tx.Commit()
+	}
+	if err != nil {
+		return 0, err
+	}
+	if affected, err = result.RowsAffected(); err != nil {
+		return 0, err
+	}
+
+	return int(affected), tx.Commit()
+}
+
+// Restore membatalkan soft delete kategori berdasarkan ID.
+// Mengembalikan NotFoundError jika tidak ada kategori terhapus dengan ID tersebut.
+func (repo *CategoryRepository) Restore(ctx context.Context, id int) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE category SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL"
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), id)
+	if err != nil {
+		return err
+	}
+
 	rows, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
 
-	// Jika tidak ada baris yang terpengaruh, kategori tidak ditemukan.
 	if rows == 0 {
-		return errors.New("kategori tidak ditemukan")
+		return &models.NotFoundError{Resource: "category", ID: id}
 	}
 
-	return err
+	return nil
 }
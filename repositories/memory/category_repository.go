@@ -0,0 +1,199 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"task-session-1/models"
+	"time"
+)
+
+// CategoryRepository adalah implementasi in-memory dari repositories.CategoryStore.
+type CategoryRepository struct {
+	store *Store
+}
+
+// NewCategoryRepository adalah konstruktor untuk membuat instance CategoryRepository in-memory.
+func NewCategoryRepository(store *Store) *CategoryRepository {
+	return &CategoryRepository{store: store}
+}
+
+// GetAll mengambil satu halaman kategori sesuai filter, pengurutan, dan paginasi,
+// beserta jumlah produk setiap kategori.
+func (repo *CategoryRepository) GetAll(ctx context.Context, filter models.CategoryFilter) (*models.Page[models.Category], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	name := strings.ToLower(filter.Name)
+	counts := repo.store.productCounts()
+	categories := make([]models.Category, 0, len(repo.store.categories))
+	for _, c := range repo.store.categories {
+		if c.DeletedAt != nil && !filter.IncludeDeleted {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(c.Name), name) {
+			continue
+		}
+		c.ProductCount = counts[c.ID]
+		categories = append(categories, c)
+	}
+
+	return paginate(categories, filter.ListOptions, categorySortKey(filter.Sort), filter.CursorFor), nil
+}
+
+// categorySortKey mengembalikan fungsi yang mengambil nilai field pengurutan kategori.
+func categorySortKey(field string) sortKey[models.Category] {
+	return func(c models.Category) (interface{}, int) {
+		if field == "name" {
+			return c.Name, c.ID
+		}
+		return c.ID, c.ID
+	}
+}
+
+// Create menyimpan kategori baru dan mengisi ID yang dihasilkan.
+func (repo *CategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	repo.store.lastCategoryID++
+	category.ID = repo.store.lastCategoryID
+	repo.store.categories[category.ID] = *category
+
+	return nil
+}
+
+// GetByID mengambil satu kategori berdasarkan ID beserta jumlah produknya.
+// Jika kategori tidak ditemukan (atau sudah dihapus dan opts.IncludeDeleted tidak aktif), mengembalikan nil tanpa error.
+func (repo *CategoryRepository) GetByID(ctx context.Context, id int, opts models.CategoryOptions) (*models.Category, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	c, ok := repo.store.categories[id]
+	if !ok || (c.DeletedAt != nil && !opts.IncludeDeleted) {
+		return nil, nil
+	}
+	c.ProductCount = repo.store.productCounts()[id]
+	return &c, nil
+}
+
+// Update memperbarui data kategori berdasarkan ID.
+func (repo *CategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if !repo.store.activeCategory(category.ID) {
+		return &models.NotFoundError{Resource: "category", ID: category.ID}
+	}
+	repo.store.categories[category.ID] = *category
+
+	return nil
+}
+
+// Patch memperbarui hanya field kategori yang diisi di patch.
+func (repo *CategoryRepository) Patch(ctx context.Context, id int, patch models.CategoryPatch) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	stored, ok := repo.store.categories[id]
+	if !ok || stored.DeletedAt != nil {
+		return &models.NotFoundError{Resource: "category", ID: id}
+	}
+
+	patch.Apply(&stored)
+	repo.store.categories[id] = stored
+
+	return nil
+}
+
+// Delete menghapus kategori berdasarkan ID secara soft delete sesuai policy (restrict, reassign, atau cascade).
+// Perubahan produk dan kategori dilakukan di bawah lock yang sama, sehingga atomik seperti transaksi SQL.
+// Mengembalikan jumlah produk yang dipindahkan atau ikut dihapus.
+func (repo *CategoryRepository) Delete(ctx context.Context, id int, policy models.CategoryDeletePolicy) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	c, ok := repo.store.categories[id]
+	if !ok || c.DeletedAt != nil {
+		return 0, &models.NotFoundError{Resource: "category", ID: id}
+	}
+
+	now := time.Now()
+	affected := 0
+	switch policy.Mode {
+	case models.CategoryDeleteReassign:
+		if !repo.store.activeCategory(policy.TargetCategoryID) {
+			return 0, models.NewValidationError("target_category_id", "category not found")
+		}
+		for pid, p := range repo.store.products {
+			if p.CategoryID == id {
+				p.CategoryID = policy.TargetCategoryID
+				repo.store.products[pid] = p
+				affected++
+			}
+		}
+	case models.CategoryDeleteCascade:
+		for pid, p := range repo.store.products {
+			if p.CategoryID == id && p.DeletedAt == nil {
+				p.DeletedAt = &now
+				repo.store.products[pid] = p
+				affected++
+			}
+		}
+	default:
+		if active := repo.store.productCounts()[id]; active > 0 {
+			return 0, &models.ConflictError{
+				Message: "category is still referenced by products",
+				Details: map[string]interface{}{"product_count": active},
+			}
+		}
+	}
+
+	c.DeletedAt = &now
+	repo.store.categories[id] = c
+
+	return affected, nil
+}
+
+// Restore membatalkan soft delete kategori berdasarkan ID.
+// Mengembalikan NotFoundError jika tidak ada kategori terhapus dengan ID tersebut.
+func (repo *CategoryRepository) Restore(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	c, ok := repo.store.categories[id]
+	if !ok || c.DeletedAt == nil {
+		return &models.NotFoundError{Resource: "category", ID: id}
+	}
+	c.DeletedAt = nil
+	repo.store.categories[id] = c
+
+	return nil
+}
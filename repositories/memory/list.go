@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"cmp"
+	"sort"
+	"task-session-1/models"
+)
+
+// sortKey mengembalikan nilai field pengurutan dan ID dari satu item.
+// Nilai field harus bertipe int atau string, sama seperti nilai di models.Cursor.
+type sortKey[T any] func(item T) (value interface{}, id int)
+
+// compareKeys membandingkan dua pasangan (nilai, ID) secara menaik.
+func compareKeys(a interface{}, aID int, b interface{}, bID int) int {
+	var c int
+	switch av := a.(type) {
+	case int:
+		bv, _ := b.(int)
+		c = cmp.Compare(av, bv)
+	case string:
+		bv, _ := b.(string)
+		c = cmp.Compare(av, bv)
+	}
+	if c != 0 {
+		return c
+	}
+	return cmp.Compare(aID, bID)
+}
+
+// paginate mengurutkan items, lalu menerapkan cursor, offset, dan limit seperti query SQL.
+// Total dihitung sebelum cursor dan offset diterapkan.
+func paginate[T any](items []T, opts models.ListOptions, key sortKey[T], cursorFor func(T) string) *models.Page[T] {
+	direction := 1
+	if opts.Desc {
+		direction = -1
+	}
+	sort.Slice(items, func(i, j int) bool {
+		iv, iid := key(items[i])
+		jv, jid := key(items[j])
+		return direction*compareKeys(iv, iid, jv, jid) < 0
+	})
+
+	page := &models.Page[T]{Items: make([]T, 0), Total: len(items)}
+
+	if opts.Cursor != nil {
+		start := sort.Search(len(items), func(i int) bool {
+			v, id := key(items[i])
+			return direction*compareKeys(v, id, opts.Cursor.Value, opts.Cursor.ID) > 0
+		})
+		items = items[start:]
+	}
+	if opts.Offset >= len(items) {
+		return page
+	}
+	items = items[opts.Offset:]
+
+	if len(items) > opts.Limit {
+		page.Items = append(page.Items, items[:opts.Limit]...)
+		page.NextCursor = cursorFor(items[opts.Limit-1])
+		return page
+	}
+	page.Items = append(page.Items, items...)
+	return page
+}
@@ -0,0 +1,401 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"strings"
+	"task-session-1/models"
+	"time"
+)
+
+// ProductRepository adalah implementasi in-memory dari repositories.ProductStore.
+type ProductRepository struct {
+	store *Store
+}
+
+// NewProductRepository adalah konstruktor untuk membuat instance ProductRepository in-memory.
+func NewProductRepository(store *Store) *ProductRepository {
+	return &ProductRepository{store: store}
+}
+
+// GetAll mengambil satu halaman produk sesuai filter, pengurutan, dan paginasi.
+// Filter nama tidak membedakan huruf besar/kecil, sama seperti ILIKE di database.
+// Pencarian (filter.Query) memakai models.SearchScore, sama seperti fungsi search_score di SQLite.
+func (repo *ProductRepository) GetAll(ctx context.Context, filter models.ProductFilter) (*models.Page[models.Product], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	name := strings.ToLower(filter.Name)
+	products := make([]models.Product, 0, len(repo.store.products))
+	for _, p := range repo.store.products {
+		if p.DeletedAt != nil && !filter.IncludeDeleted {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(p.Name), name) {
+			continue
+		}
+		if filter.CategoryID != 0 && p.CategoryID != filter.CategoryID {
+			continue
+		}
+		if filter.MinPrice != nil && p.Price < *filter.MinPrice {
+			continue
+		}
+		if filter.MaxPrice != nil && p.Price > *filter.MaxPrice {
+			continue
+		}
+		if filter.InStock != nil && (p.Stock > 0) != *filter.InStock {
+			continue
+		}
+		if filter.Query != "" {
+			p.Score = models.SearchScore(filter.Query, p.Name, p.SKU, repo.store.categories[p.CategoryID].Name)
+			if p.Score == 0 {
+				continue
+			}
+		}
+		products = append(products, p)
+	}
+
+	page := paginate(products, filter.ListOptions, productSortKey(filter.Sort), filter.CursorFor)
+	if filter.IncludeCategory {
+		counts := repo.store.productCounts()
+		for i := range page.Items {
+			repo.withCategory(&page.Items[i], counts)
+		}
+	}
+	return page, nil
+}
+
+// withCategory mengisi kategori produk beserta jumlah produknya, seperti JOIN di repository SQL.
+// Pemanggil harus sudah memegang lock store.
+func (repo *ProductRepository) withCategory(p *models.Product, counts map[int]int) {
+	if c, ok := repo.store.categories[p.CategoryID]; ok {
+		c.ProductCount = counts[c.ID]
+		p.Category = &c
+	}
+}
+
+// productSortKey mengembalikan fungsi yang mengambil nilai field pengurutan produk.
+func productSortKey(field string) sortKey[models.Product] {
+	return func(p models.Product) (interface{}, int) {
+		switch field {
+		case "name":
+			return p.Name, p.ID
+		case "price":
+			return p.Price, p.ID
+		case "stock":
+			return p.Stock, p.ID
+		case "relevance":
+			return p.Score, p.ID
+		default:
+			return p.ID, p.ID
+		}
+	}
+}
+
+// Create menyimpan produk baru dan mengisi ID yang dihasilkan.
+// Kategori produk harus sudah ada, sama seperti foreign key di database.
+func (repo *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if !repo.store.activeCategory(product.CategoryID) {
+		return models.NewValidationError("category_id", "category not found")
+	}
+	if repo.store.identifierTaken(*product) {
+		return &models.ConflictError{Message: "sku or barcode is already used by another product"}
+	}
+
+	repo.store.lastProductID++
+	product.ID = repo.store.lastProductID
+	stored := *product
+	stored.Category = nil
+	repo.store.products[product.ID] = stored
+	repo.store.recordMovement(models.StockMovement{
+		ProductID:  product.ID,
+		Delta:      product.Stock,
+		StockAfter: product.Stock,
+		Reason:     models.StockInitial,
+	})
+
+	return nil
+}
+
+// GetByID mengambil satu produk berdasarkan ID, beserta kategorinya jika opts.IncludeCategory aktif.
+// Jika produk tidak ditemukan, mengembalikan nil tanpa error.
+func (repo *ProductRepository) GetByID(ctx context.Context, id int, opts models.ProductOptions) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	p, ok := repo.store.products[id]
+	if !ok || (p.DeletedAt != nil && !opts.IncludeDeleted) {
+		return nil, nil
+	}
+	if opts.IncludeCategory {
+		repo.withCategory(&p, repo.store.productCounts())
+	}
+	return &p, nil
+}
+
+// GetByBarcode mengambil produk aktif dengan barcode tertentu, atau nil jika tidak ada.
+func (repo *ProductRepository) GetByBarcode(ctx context.Context, barcode string, opts models.ProductOptions) (*models.Product, error) {
+	return repo.getByCode(ctx, opts, func(p models.Product) bool { return p.Barcode == barcode })
+}
+
+// GetBySKU mengambil produk aktif dengan SKU tertentu, atau nil jika tidak ada.
+func (repo *ProductRepository) GetBySKU(ctx context.Context, sku string, opts models.ProductOptions) (*models.Product, error) {
+	return repo.getByCode(ctx, opts, func(p models.Product) bool { return p.SKU == sku })
+}
+
+// getByCode mengambil produk aktif pertama yang cocok menurut match, beserta kategorinya jika diminta.
+func (repo *ProductRepository) getByCode(ctx context.Context, opts models.ProductOptions, match func(p models.Product) bool) (*models.Product, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	p, ok := repo.store.productByCode(match)
+	if !ok {
+		return nil, nil
+	}
+	if opts.IncludeCategory {
+		repo.withCategory(&p, repo.store.productCounts())
+	}
+	return &p, nil
+}
+
+// Update memperbarui nama, harga, kategori, SKU, dan barcode produk berdasarkan ID.
+// Stok tidak ikut diubah; product.Stock diisi dengan stok saat ini.
+func (repo *ProductRepository) Update(ctx context.Context, product *models.Product) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	old, ok := repo.store.activeProduct(product.ID)
+	if !ok {
+		return &models.NotFoundError{Resource: "product", ID: product.ID}
+	}
+	if !repo.store.activeCategory(product.CategoryID) {
+		return models.NewValidationError("category_id", "category not found")
+	}
+	if repo.store.identifierTaken(*product) {
+		return &models.ConflictError{Message: "sku or barcode is already used by another product"}
+	}
+
+	product.Stock = old.Stock
+	stored := *product
+	stored.Category = nil
+	repo.store.products[product.ID] = stored
+
+	return nil
+}
+
+// Import menyimpan banyak produk sekaligus: produk dengan ID 0 dibuat baru (ID-nya diisi),
+// dan produk lain diperbarui seperti Update tanpa mengubah stok. Jika satu produk gagal, semua perubahan, termasuk
+// ledger stok, dibatalkan sehingga hasilnya sama seperti transaksi di repository SQL.
+func (repo *ProductRepository) Import(ctx context.Context, products []models.Product) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	saved, lastID := maps.Clone(repo.store.products), repo.store.lastProductID
+	movements, lastMovementID := len(repo.store.movements), repo.store.lastMovementID
+	for i := range products {
+		if err := repo.importOne(&products[i]); err != nil {
+			repo.store.products, repo.store.lastProductID = saved, lastID
+			repo.store.movements, repo.store.lastMovementID = repo.store.movements[:movements], lastMovementID
+			for j := range products[:i] {
+				if products[j].ID > lastID {
+					products[j].ID = 0
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// importOne membuat atau memperbarui satu produk untuk Import.
+// Pemanggil harus sudah memegang lock store.
+func (repo *ProductRepository) importOne(product *models.Product) error {
+	var old models.Product
+	if product.ID != 0 {
+		var ok bool
+		if old, ok = repo.store.activeProduct(product.ID); !ok {
+			return &models.NotFoundError{Resource: "product", ID: product.ID}
+		}
+		// Seperti Update, stok produk yang sudah ada tidak ikut diubah.
+		product.Stock = old.Stock
+	}
+	if !repo.store.activeCategory(product.CategoryID) {
+		return models.NewValidationError("category_id", "category not found")
+	}
+	if repo.store.identifierTaken(*product) {
+		return &models.ConflictError{Message: "sku or barcode is already used by another product"}
+	}
+
+	if product.ID == 0 {
+		repo.store.lastProductID++
+		product.ID = repo.store.lastProductID
+	}
+	stored := *product
+	stored.Category = nil
+	repo.store.products[product.ID] = stored
+	repo.store.recordMovement(models.StockMovement{
+		ProductID:  product.ID,
+		Delta:      product.Stock - old.Stock,
+		StockAfter: product.Stock,
+		Reason:     models.StockImport,
+	})
+	return nil
+}
+
+// Patch memperbarui hanya field produk yang diisi di patch.
+func (repo *ProductRepository) Patch(ctx context.Context, id int, patch models.ProductPatch) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	stored, ok := repo.store.activeProduct(id)
+	if !ok {
+		return &models.NotFoundError{Resource: "product", ID: id}
+	}
+	if patch.CategoryID != nil {
+		if !repo.store.activeCategory(*patch.CategoryID) {
+			return models.NewValidationError("category_id", "category not found")
+		}
+	}
+
+	patch.Apply(&stored)
+	if repo.store.identifierTaken(stored) {
+		return &models.ConflictError{Message: "sku or barcode is already used by another product"}
+	}
+	repo.store.products[id] = stored
+
+	return nil
+}
+
+// Delete menghapus produk berdasarkan ID secara soft delete (mengisi DeletedAt).
+// Produk tetap disimpan agar transaksi dan laporan lama masih bisa menampilkan namanya.
+func (repo *ProductRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	p, ok := repo.store.activeProduct(id)
+	if !ok {
+		return &models.NotFoundError{Resource: "product", ID: id}
+	}
+
+	now := time.Now()
+	p.DeletedAt = &now
+	repo.store.products[id] = p
+
+	return nil
+}
+
+// Restore membatalkan soft delete produk berdasarkan ID.
+// Mengembalikan NotFoundError jika tidak ada produk terhapus dengan ID tersebut.
+func (repo *ProductRepository) Restore(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	p, ok := repo.store.products[id]
+	if !ok || p.DeletedAt == nil {
+		return &models.NotFoundError{Resource: "product", ID: id}
+	}
+	// SKU atau barcode produk mungkin sudah dipakai produk lain selama produk ini dihapus.
+	if repo.store.identifierTaken(p) {
+		return &models.ConflictError{Message: "sku or barcode is already used by another product"}
+	}
+	p.DeletedAt = nil
+	repo.store.products[id] = p
+
+	return nil
+}
+
+// ListLowStock mengambil produk aktif tanpa varian dan varian aktif yang stoknya sudah mencapai batas pemesanan
+// ulang produknya, diurutkan berdasarkan produk lalu varian.
+func (repo *ProductRepository) ListLowStock(ctx context.Context, filter models.LowStockFilter) ([]models.LowStockItem, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	items := make([]models.LowStockItem, 0)
+	for _, p := range repo.store.products {
+		if p.DeletedAt != nil || (filter.CategoryID != 0 && p.CategoryID != filter.CategoryID) {
+			continue
+		}
+		item := models.LowStockItem{
+			ProductID:    p.ID,
+			Name:         p.Name,
+			SKU:          p.SKU,
+			CategoryID:   p.CategoryID,
+			Stock:        p.Stock,
+			ReorderLevel: p.ReorderLevel,
+			ReorderQty:   p.ReorderQty,
+		}
+		if !repo.store.hasVariants(p.ID) {
+			if models.IsLowStock(p.Stock, p.ReorderLevel) {
+				items = append(items, item)
+			}
+			continue
+		}
+		for _, v := range repo.store.variants {
+			if v.ProductID != p.ID || v.DeletedAt != nil || !models.IsLowStock(v.Stock, p.ReorderLevel) {
+				continue
+			}
+			variantItem := item
+			variantItem.VariantID = &v.ID
+			variantItem.VariantName = v.Label()
+			variantItem.SKU = v.SKU
+			variantItem.Stock = v.Stock
+			items = append(items, variantItem)
+		}
+	}
+
+	slices.SortFunc(items, func(a, b models.LowStockItem) int {
+		variantID := func(item models.LowStockItem) int {
+			if item.VariantID == nil {
+				return 0
+			}
+			return *item.VariantID
+		}
+		return cmp.Or(cmp.Compare(a.ProductID, b.ProductID), cmp.Compare(variantID(a), variantID(b)))
+	})
+	return items, nil
+}
@@ -0,0 +1,216 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"task-session-1/models"
+	"time"
+)
+
+// PurchaseOrderRepository adalah implementasi in-memory dari repositories.PurchaseOrderStore.
+type PurchaseOrderRepository struct {
+	store *Store
+}
+
+// NewPurchaseOrderRepository adalah konstruktor untuk membuat instance PurchaseOrderRepository in-memory.
+func NewPurchaseOrderRepository(store *Store) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{store: store}
+}
+
+// orderView menyalin purchase order dari store beserta barisnya, lalu mengisi nama supplier, nama produk,
+// dan total biaya seperti hasil join di database. Pemanggil harus sudah memegang lock store.
+func (repo *PurchaseOrderRepository) orderView(po models.PurchaseOrder) models.PurchaseOrder {
+	po.SupplierName = repo.store.suppliers[po.SupplierID].Name
+	po.Items = slices.Clone(po.Items)
+	for i := range po.Items {
+		po.Items[i].ProductName = repo.store.products[po.Items[i].ProductID].Name
+	}
+	po.UpdateTotals()
+	return po
+}
+
+// GetAll mengambil satu halaman purchase order beserta barisnya, difilter berdasarkan status dan supplier.
+func (repo *PurchaseOrderRepository) GetAll(ctx context.Context, filter models.PurchaseOrderFilter) (*models.Page[models.PurchaseOrder], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	statuses := filter.Statuses()
+	orders := make([]models.PurchaseOrder, 0)
+	for _, po := range repo.store.orders {
+		if len(statuses) > 0 && !slices.Contains(statuses, po.Status) {
+			continue
+		}
+		if filter.SupplierID != 0 && po.SupplierID != filter.SupplierID {
+			continue
+		}
+		orders = append(orders, repo.orderView(po))
+	}
+
+	key := func(po models.PurchaseOrder) (interface{}, int) { return po.ID, po.ID }
+	return paginate(orders, filter.ListOptions, key, filter.CursorFor), nil
+}
+
+// Create menyimpan purchase order baru berstatus ordered beserta barisnya, lalu mengisi ID, Status,
+// CreatedAt, dan UpdatedAt purchase order serta ID setiap baris.
+func (repo *PurchaseOrderRepository) Create(ctx context.Context, po *models.PurchaseOrder) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	repo.store.lastOrderID++
+	po.ID = repo.store.lastOrderID
+	po.Status = models.PurchaseOrderOrdered
+	po.CreatedAt = time.Now()
+	po.UpdatedAt = po.CreatedAt
+	for i := range po.Items {
+		repo.store.lastOrderItemID++
+		po.Items[i].ID = repo.store.lastOrderItemID
+		po.Items[i].ReceivedQuantity = 0
+	}
+	po.UpdateTotals()
+
+	stored := *po
+	stored.Items = slices.Clone(po.Items)
+	repo.store.orders[po.ID] = stored
+	return nil
+}
+
+// GetByID mengambil satu purchase order beserta barisnya. Jika tidak ditemukan, mengembalikan nil tanpa error.
+func (repo *PurchaseOrderRepository) GetByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	po, ok := repo.store.orders[id]
+	if !ok {
+		return nil, nil
+	}
+	view := repo.orderView(po)
+	return &view, nil
+}
+
+// openOrder mengambil purchase order id yang masih open.
+// Pemanggil harus sudah memegang lock store.
+func (repo *PurchaseOrderRepository) openOrder(id int) (models.PurchaseOrder, error) {
+	po, ok := repo.store.orders[id]
+	if !ok {
+		return po, &models.NotFoundError{Resource: "purchase order", ID: id}
+	}
+	if !po.Status.IsOpen() {
+		return po, models.NewPurchaseOrderClosedError(id, po.Status)
+	}
+	return po, nil
+}
+
+// Receive menerima barang untuk purchase order id: received_quantity setiap baris, stok produk atau varian,
+// ledger stok, dan status purchase order berubah bersama. Semua baris diperiksa sebelum ada yang diubah,
+// sehingga kegagalan tidak meninggalkan perubahan sebagian.
+func (repo *PurchaseOrderRepository) Receive(ctx context.Context, id int, receipt models.PurchaseOrderReceipt) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	po, err := repo.openOrder(id)
+	if err != nil {
+		return err
+	}
+	po.Items = slices.Clone(po.Items)
+
+	quantities, err := po.ReceiptQuantities(receipt)
+	if err != nil {
+		return err
+	}
+
+	// Produk atau varian yang sudah dihapus tidak bisa menerima stok.
+	for i, item := range po.Items {
+		if quantities[i] == 0 {
+			continue
+		}
+		if item.VariantID != nil {
+			if _, ok := repo.store.activeVariant(*item.VariantID); !ok {
+				return deletedStockTargetError("variant", *item.VariantID, item.ID)
+			}
+		} else if _, ok := repo.store.activeProduct(item.ProductID); !ok {
+			return deletedStockTargetError("product", item.ProductID, item.ID)
+		}
+	}
+
+	reference := models.Reference("purchase_order", id)
+	for i := range po.Items {
+		item := &po.Items[i]
+		quantity := quantities[i]
+		if quantity == 0 {
+			continue
+		}
+		item.ReceivedQuantity += quantity
+
+		var stockAfter int
+		if item.VariantID != nil {
+			v := repo.store.variants[*item.VariantID]
+			v.Stock += quantity
+			repo.store.variants[v.ID] = v
+			stockAfter = v.Stock
+		} else {
+			p := repo.store.products[item.ProductID]
+			p.Stock += quantity
+			repo.store.products[p.ID] = p
+			stockAfter = p.Stock
+		}
+
+		repo.store.recordMovement(models.StockMovement{
+			ProductID:  item.ProductID,
+			VariantID:  item.VariantID,
+			Delta:      quantity,
+			StockAfter: stockAfter,
+			Reason:     models.StockPurchaseReceipt,
+			Reference:  reference,
+		})
+	}
+
+	po.Status = po.ReceivedStatus()
+	po.UpdatedAt = time.Now()
+	repo.store.orders[id] = po
+	return nil
+}
+
+// deletedStockTargetError membuat ConflictError untuk baris purchase order itemID yang produk atau variannya
+// sudah dihapus, sama seperti repository SQL.
+func deletedStockTargetError(resource string, id, itemID int) error {
+	return &models.ConflictError{
+		Message: fmt.Sprintf("%s %d has been deleted", resource, id),
+		Details: map[string]interface{}{"item_id": itemID},
+	}
+}
+
+// Cancel membatalkan purchase order yang masih open. Barang yang sudah diterima tetap tercatat.
+func (repo *PurchaseOrderRepository) Cancel(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	po, err := repo.openOrder(id)
+	if err != nil {
+		return err
+	}
+	po.Status = models.PurchaseOrderCancelled
+	po.UpdatedAt = time.Now()
+	repo.store.orders[id] = po
+	return nil
+}
@@ -0,0 +1,271 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"task-session-1/models"
+	"time"
+)
+
+// StockCountRepository adalah implementasi in-memory dari repositories.StockCountStore.
+type StockCountRepository struct {
+	store *Store
+}
+
+// NewStockCountRepository adalah konstruktor untuk membuat instance StockCountRepository in-memory.
+func NewStockCountRepository(store *Store) *StockCountRepository {
+	return &StockCountRepository{store: store}
+}
+
+// countView menyalin sesi stock opname dari store beserta barisnya, lalu mengisi nama produk, nama varian, SKU,
+// dan ringkasan seperti hasil join di database. Pemanggil harus sudah memegang lock store.
+func (repo *StockCountRepository) countView(c models.StockCount) models.StockCount {
+	c.Items = slices.Clone(c.Items)
+	for i := range c.Items {
+		item := &c.Items[i]
+		p := repo.store.products[item.ProductID]
+		item.Name, item.SKU = p.Name, p.SKU
+		if item.VariantID != nil {
+			v := repo.store.variants[*item.VariantID]
+			item.VariantName, item.SKU = v.Label(), v.SKU
+		}
+	}
+	c.UpdateSummary()
+	return c
+}
+
+// GetAll mengambil satu halaman sesi stock opname tanpa barisnya, difilter berdasarkan status.
+func (repo *StockCountRepository) GetAll(ctx context.Context, filter models.StockCountFilter) (*models.Page[models.StockCount], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	counts := make([]models.StockCount, 0)
+	for _, c := range repo.store.stockCounts {
+		if filter.Status != "" && c.Status != filter.Status {
+			continue
+		}
+		c.Items = nil
+		counts = append(counts, c)
+	}
+
+	key := func(c models.StockCount) (interface{}, int) { return c.ID, c.ID }
+	return paginate(counts, filter.ListOptions, key, filter.CursorFor), nil
+}
+
+// Create membuka sesi stock opname baru bersama salinan stok setiap produk aktif tanpa varian dan setiap varian
+// aktif (hanya kategori count.CategoryID jika diisi), lalu mengisi ID, Status, CreatedAt, dan UpdatedAt sesi.
+// Mengembalikan ConflictError jika masih ada sesi lain yang open.
+func (repo *StockCountRepository) Create(ctx context.Context, count *models.StockCount) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	for _, c := range repo.store.stockCounts {
+		if c.Status == models.StockCountOpen {
+			return models.NewStockCountOpenError(c.ID)
+		}
+	}
+
+	items := make([]models.StockCountItem, 0)
+	for _, p := range repo.store.products {
+		if p.DeletedAt != nil || (count.CategoryID != nil && p.CategoryID != *count.CategoryID) {
+			continue
+		}
+		if !repo.store.hasVariants(p.ID) {
+			items = append(items, models.StockCountItem{ProductID: p.ID, SystemQuantity: p.Stock})
+			continue
+		}
+		for _, v := range repo.store.variants {
+			if v.ProductID == p.ID && v.DeletedAt == nil {
+				items = append(items, models.StockCountItem{ProductID: p.ID, VariantID: &v.ID, SystemQuantity: v.Stock})
+			}
+		}
+	}
+	slices.SortFunc(items, func(a, b models.StockCountItem) int {
+		variantID := func(item models.StockCountItem) int {
+			if item.VariantID == nil {
+				return 0
+			}
+			return *item.VariantID
+		}
+		return cmp.Or(cmp.Compare(a.ProductID, b.ProductID), cmp.Compare(variantID(a), variantID(b)))
+	})
+	for i := range items {
+		repo.store.lastStockCountItemID++
+		items[i].ID = repo.store.lastStockCountItemID
+	}
+
+	repo.store.lastStockCountID++
+	count.ID = repo.store.lastStockCountID
+	count.Status = models.StockCountOpen
+	count.CreatedAt = time.Now()
+	count.UpdatedAt = count.CreatedAt
+
+	stored := *count
+	stored.Summary, stored.Items = nil, items
+	repo.store.stockCounts[count.ID] = stored
+	return nil
+}
+
+// GetByID mengambil satu sesi stock opname beserta barisnya dan ringkasannya.
+// Jika tidak ditemukan, mengembalikan nil tanpa error.
+func (repo *StockCountRepository) GetByID(ctx context.Context, id int) (*models.StockCount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	c, ok := repo.store.stockCounts[id]
+	if !ok {
+		return nil, nil
+	}
+	view := repo.countView(c)
+	return &view, nil
+}
+
+// openCount mengambil sesi stock opname id yang masih open.
+// Pemanggil harus sudah memegang lock store.
+func (repo *StockCountRepository) openCount(id int) (models.StockCount, error) {
+	c, ok := repo.store.stockCounts[id]
+	if !ok {
+		return c, &models.NotFoundError{Resource: "stock count", ID: id}
+	}
+	if c.Status != models.StockCountOpen {
+		return c, models.NewStockCountClosedError(id, c.Status)
+	}
+	return c, nil
+}
+
+// RecordCounts menyimpan hasil hitung fisik sesi stock opname id, dengan key ID baris dan nilai jumlah yang
+// dihitung. Hasil hitung sebelumnya ditimpa.
+func (repo *StockCountRepository) RecordCounts(ctx context.Context, id int, counts map[int]int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	c, err := repo.openCount(id)
+	if err != nil {
+		return err
+	}
+	c.Items = slices.Clone(c.Items)
+	for i := range c.Items {
+		if quantity, ok := counts[c.Items[i].ID]; ok {
+			c.Items[i].CountedQuantity = &quantity
+		}
+	}
+	c.UpdatedAt = time.Now()
+	repo.store.stockCounts[id] = c
+	return nil
+}
+
+// Post memposting sesi stock opname id: selisih setiap baris yang sudah dihitung ditambahkan ke stok produk atau
+// varian saat ini dan dicatat di ledger stok, lalu status sesi menjadi posted. Semua baris diperiksa sebelum ada
+// yang diubah, sehingga kegagalan tidak meninggalkan perubahan sebagian. Produk atau varian yang sudah dihapus
+// sejak sesi dibuka dilewati.
+func (repo *StockCountRepository) Post(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	c, err := repo.openCount(id)
+	if err != nil {
+		return err
+	}
+
+	// stockTarget adalah baris yang stoknya akan diubah beserta stok barunya.
+	type stockTarget struct {
+		item       models.StockCountItem
+		variance   int
+		stockAfter int
+	}
+	var targets []stockTarget
+	for _, item := range c.Items {
+		if item.CountedQuantity == nil || *item.CountedQuantity == item.SystemQuantity {
+			continue
+		}
+		variance := *item.CountedQuantity - item.SystemQuantity
+
+		var stock, variantID int
+		if item.VariantID != nil {
+			v, ok := repo.store.activeVariant(*item.VariantID)
+			if !ok {
+				continue
+			}
+			stock, variantID = v.Stock, v.ID
+		} else {
+			p, ok := repo.store.activeProduct(item.ProductID)
+			if !ok {
+				continue
+			}
+			stock = p.Stock
+		}
+		if stock+variance < 0 {
+			return &models.InsufficientStockError{ProductID: item.ProductID, VariantID: variantID, Requested: -variance, Available: stock}
+		}
+		targets = append(targets, stockTarget{item: item, variance: variance, stockAfter: stock + variance})
+	}
+
+	reference := models.Reference("stock_count", id)
+	for _, t := range targets {
+		if t.item.VariantID != nil {
+			v := repo.store.variants[*t.item.VariantID]
+			v.Stock = t.stockAfter
+			repo.store.variants[v.ID] = v
+		} else {
+			p := repo.store.products[t.item.ProductID]
+			p.Stock = t.stockAfter
+			repo.store.products[p.ID] = p
+		}
+
+		repo.store.recordMovement(models.StockMovement{
+			ProductID:  t.item.ProductID,
+			VariantID:  t.item.VariantID,
+			Delta:      t.variance,
+			StockAfter: t.stockAfter,
+			Reason:     models.StockOpname,
+			Reference:  reference,
+		})
+	}
+
+	now := time.Now()
+	c.Status = models.StockCountPosted
+	c.PostedAt = &now
+	c.UpdatedAt = now
+	repo.store.stockCounts[id] = c
+	return nil
+}
+
+// Cancel membatalkan sesi stock opname yang masih open tanpa mengubah stok.
+func (repo *StockCountRepository) Cancel(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	c, err := repo.openCount(id)
+	if err != nil {
+		return err
+	}
+	c.Status = models.StockCountCancelled
+	c.UpdatedAt = time.Now()
+	repo.store.stockCounts[id] = c
+	return nil
+}
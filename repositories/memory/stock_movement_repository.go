@@ -0,0 +1,135 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"task-session-1/models"
+)
+
+// StockMovementRepository adalah implementasi in-memory dari repositories.StockMovementStore.
+// Pergerakan stok ditulis oleh repository produk, varian, dan transaksi lewat Store.recordMovement.
+type StockMovementRepository struct {
+	store *Store
+}
+
+// NewStockMovementRepository adalah konstruktor untuk membuat instance StockMovementRepository.
+func NewStockMovementRepository(store *Store) *StockMovementRepository {
+	return &StockMovementRepository{store: store}
+}
+
+// Adjust menambahkan m.Delta ke stok produk m.ProductID, atau ke stok varian m.VariantID, lalu mencatatnya
+// di ledger. m.ID, m.StockAfter, dan m.CreatedAt diisi. Stok yang akan menjadi negatif ditolak dengan
+// InsufficientStockError tanpa mengubah apa pun.
+func (repo *StockMovementRepository) Adjust(ctx context.Context, m *models.StockMovement) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if m.VariantID != nil {
+		v, ok := repo.store.activeVariant(*m.VariantID)
+		if !ok {
+			return &models.NotFoundError{Resource: "variant", ID: *m.VariantID}
+		}
+		if v.Stock+m.Delta < 0 {
+			return &models.InsufficientStockError{ProductID: m.ProductID, VariantID: v.ID, Requested: -m.Delta, Available: v.Stock}
+		}
+		v.Stock += m.Delta
+		repo.store.variants[v.ID] = v
+		m.StockAfter = v.Stock
+	} else {
+		p, ok := repo.store.activeProduct(m.ProductID)
+		if !ok {
+			return &models.NotFoundError{Resource: "product", ID: m.ProductID}
+		}
+		if p.Stock+m.Delta < 0 {
+			return &models.InsufficientStockError{ProductID: p.ID, Requested: -m.Delta, Available: p.Stock}
+		}
+		p.Stock += m.Delta
+		repo.store.products[p.ID] = p
+		m.StockAfter = p.Stock
+	}
+
+	*m = repo.store.recordMovement(*m)
+	return nil
+}
+
+// ListByProduct mengambil satu halaman riwayat stok produk filter.ProductID, termasuk pergerakan variannya
+// kecuali filter.VariantID diisi.
+func (repo *StockMovementRepository) ListByProduct(ctx context.Context, filter models.StockMovementFilter) (*models.Page[models.StockMovement], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	movements := make([]models.StockMovement, 0)
+	for _, m := range repo.store.movements {
+		if m.ProductID != filter.ProductID {
+			continue
+		}
+		if filter.VariantID != nil && (m.VariantID == nil || *m.VariantID != *filter.VariantID) {
+			continue
+		}
+		if filter.Reason != "" && m.Reason != filter.Reason {
+			continue
+		}
+		movements = append(movements, m)
+	}
+
+	key := func(m models.StockMovement) (interface{}, int) { return m.ID, m.ID }
+	return paginate(movements, filter.ListOptions, key, filter.CursorFor), nil
+}
+
+// Reconcile membandingkan stok setiap produk (termasuk yang sudah dihapus) dan setiap varian dengan jumlah
+// delta di ledger, lalu mengembalikan yang tidak cocok, diurutkan berdasarkan produk lalu varian.
+func (repo *StockMovementRepository) Reconcile(ctx context.Context) ([]models.StockDiscrepancy, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	productSums := make(map[int]int)
+	variantSums := make(map[int]int)
+	for _, m := range repo.store.movements {
+		if m.VariantID != nil {
+			variantSums[*m.VariantID] += m.Delta
+		} else {
+			productSums[m.ProductID] += m.Delta
+		}
+	}
+
+	discrepancies := make([]models.StockDiscrepancy, 0)
+	for _, p := range repo.store.products {
+		if p.Stock != productSums[p.ID] {
+			discrepancies = append(discrepancies, models.StockDiscrepancy{
+				ProductID: p.ID, Stock: p.Stock, LedgerStock: productSums[p.ID],
+			})
+		}
+	}
+	for _, v := range repo.store.variants {
+		if v.Stock != variantSums[v.ID] {
+			id := v.ID
+			discrepancies = append(discrepancies, models.StockDiscrepancy{
+				ProductID: v.ProductID, VariantID: &id, Stock: v.Stock, LedgerStock: variantSums[v.ID],
+			})
+		}
+	}
+
+	slices.SortFunc(discrepancies, func(a, b models.StockDiscrepancy) int {
+		variantID := func(d models.StockDiscrepancy) int {
+			if d.VariantID == nil {
+				return 0
+			}
+			return *d.VariantID
+		}
+		return cmp.Or(cmp.Compare(a.ProductID, b.ProductID), cmp.Compare(variantID(a), variantID(b)))
+	})
+	return discrepancies, nil
+}
@@ -0,0 +1,148 @@
+// Package memory menyediakan implementasi repository yang menyimpan data di memori.
+// Cocok untuk menjalankan API secara lokal dan untuk unit test tanpa database.
+// Semua data hilang saat proses berhenti.
+package memory
+
+import (
+	"maps"
+	"sync"
+	"task-session-1/models"
+	"task-session-1/repositories"
+	"time"
+)
+
+// Store adalah penyimpanan in-memory bersama untuk semua repository.
+// Semua akses dilindungi oleh mu sehingga aman dipakai dari banyak goroutine,
+// dan operasi yang menyentuh beberapa entitas (misalnya checkout) bisa atomik.
+type Store struct {
+	mu sync.RWMutex
+
+	categories   map[int]models.Category
+	products     map[int]models.Product
+	variants     map[int]models.ProductVariant
+	transactions []models.Transaction
+	movements    []models.StockMovement
+	suppliers    map[int]models.Supplier
+	orders       map[int]models.PurchaseOrder
+	stockCounts  map[int]models.StockCount
+
+	lastCategoryID       int
+	lastProductID        int
+	lastVariantID        int
+	lastTransactionID    int
+	lastDetailID         int
+	lastMovementID       int
+	lastSupplierID       int
+	lastOrderID          int
+	lastOrderItemID      int
+	lastStockCountID     int
+	lastStockCountItemID int
+}
+
+// NewStore adalah konstruktor untuk membuat Store kosong yang siap digunakan.
+func NewStore() *Store {
+	return &Store{
+		categories:  make(map[int]models.Category),
+		products:    make(map[int]models.Product),
+		variants:    make(map[int]models.ProductVariant),
+		suppliers:   make(map[int]models.Supplier),
+		orders:      make(map[int]models.PurchaseOrder),
+		stockCounts: make(map[int]models.StockCount),
+	}
+}
+
+// productCounts menghitung jumlah produk aktif (belum dihapus) per kategori.
+// Pemanggil harus sudah memegang lock store.
+func (s *Store) productCounts() map[int]int {
+	counts := make(map[int]int, len(s.categories))
+	for _, p := range s.products {
+		if p.DeletedAt == nil {
+			counts[p.CategoryID]++
+		}
+	}
+	return counts
+}
+
+// activeCategory mengembalikan true jika kategori ada dan belum dihapus.
+// Pemanggil harus sudah memegang lock store.
+func (s *Store) activeCategory(id int) bool {
+	c, ok := s.categories[id]
+	return ok && c.DeletedAt == nil
+}
+
+// activeProduct mengambil produk yang ada dan belum dihapus.
+// Pemanggil harus sudah memegang lock store.
+func (s *Store) activeProduct(id int) (models.Product, bool) {
+	p, ok := s.products[id]
+	return p, ok && p.DeletedAt == nil
+}
+
+// productByCode mencari produk aktif yang cocok menurut match, misalnya barcode yang sama.
+// Pemanggil harus sudah memegang lock store.
+func (s *Store) productByCode(match func(p models.Product) bool) (models.Product, bool) {
+	for _, p := range s.products {
+		if p.DeletedAt == nil && match(p) {
+			return p, true
+		}
+	}
+	return models.Product{}, false
+}
+
+// identifierTaken mengembalikan true jika SKU atau barcode product sudah dipakai produk aktif lain,
+// sama seperti unique index di database. Pemanggil harus sudah memegang lock store.
+func (s *Store) identifierTaken(product models.Product) bool {
+	_, taken := s.productByCode(func(p models.Product) bool {
+		return p.ID != product.ID &&
+			(product.SKU != "" && p.SKU == product.SKU || product.Barcode != "" && p.Barcode == product.Barcode)
+	})
+	return taken
+}
+
+// activeVariant mengambil varian yang ada dan belum dihapus.
+// Opsi varian disalin agar perubahan oleh pemanggil tidak mengubah data di store.
+// Pemanggil harus sudah memegang lock store.
+func (s *Store) activeVariant(id int) (models.ProductVariant, bool) {
+	v, ok := s.variants[id]
+	if !ok || v.DeletedAt != nil {
+		return models.ProductVariant{}, false
+	}
+	v.Options = maps.Clone(v.Options)
+	return v, true
+}
+
+// hasVariants mengembalikan true jika produk punya minimal satu varian aktif.
+// Pemanggil harus sudah memegang lock store.
+func (s *Store) hasVariants(productID int) bool {
+	for _, v := range s.variants {
+		if v.ProductID == productID && v.DeletedAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// recordMovement menambahkan pergerakan stok ke ledger, mengisi ID dan waktunya, lalu mengembalikan
+// pergerakan yang disimpan. Delta 0 tidak dicatat.
+// Pemanggil harus sudah memegang lock store untuk menulis.
+func (s *Store) recordMovement(m models.StockMovement) models.StockMovement {
+	if m.Delta == 0 {
+		return m
+	}
+	s.lastMovementID++
+	m.ID = s.lastMovementID
+	m.CreatedAt = time.Now()
+	s.movements = append(s.movements, m)
+	return m
+}
+
+// Memastikan repository in-memory memenuhi interface yang sama dengan repository SQL.
+var (
+	_ repositories.CategoryStore      = (*CategoryRepository)(nil)
+	_ repositories.ProductStore       = (*ProductRepository)(nil)
+	_ repositories.PurchaseOrderStore = (*PurchaseOrderRepository)(nil)
+	_ repositories.StockCountStore    = (*StockCountRepository)(nil)
+	_ repositories.StockMovementStore = (*StockMovementRepository)(nil)
+	_ repositories.SupplierStore      = (*SupplierRepository)(nil)
+	_ repositories.TransactionStore   = (*TransactionRepository)(nil)
+	_ repositories.VariantStore       = (*VariantRepository)(nil)
+)
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"task-session-1/models"
+)
+
+// SupplierRepository adalah implementasi in-memory dari repositories.SupplierStore.
+type SupplierRepository struct {
+	store *Store
+}
+
+// NewSupplierRepository adalah konstruktor untuk membuat instance SupplierRepository in-memory.
+func NewSupplierRepository(store *Store) *SupplierRepository {
+	return &SupplierRepository{store: store}
+}
+
+// GetAll mengambil satu halaman supplier sesuai filter nama, pengurutan, dan paginasi.
+func (repo *SupplierRepository) GetAll(ctx context.Context, filter models.SupplierFilter) (*models.Page[models.Supplier], error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	name := strings.ToLower(filter.Name)
+	suppliers := make([]models.Supplier, 0, len(repo.store.suppliers))
+	for _, s := range repo.store.suppliers {
+		if name != "" && !strings.Contains(strings.ToLower(s.Name), name) {
+			continue
+		}
+		suppliers = append(suppliers, s)
+	}
+
+	key := func(s models.Supplier) (interface{}, int) {
+		if filter.Sort == "name" {
+			return s.Name, s.ID
+		}
+		return s.ID, s.ID
+	}
+	return paginate(suppliers, filter.ListOptions, key, filter.CursorFor), nil
+}
+
+// Create menyimpan supplier baru dan mengisi ID yang dihasilkan.
+func (repo *SupplierRepository) Create(ctx context.Context, supplier *models.Supplier) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	repo.store.lastSupplierID++
+	supplier.ID = repo.store.lastSupplierID
+	repo.store.suppliers[supplier.ID] = *supplier
+	return nil
+}
+
+// GetByID mengambil satu supplier berdasarkan ID. Jika tidak ditemukan, mengembalikan nil tanpa error.
+func (repo *SupplierRepository) GetByID(ctx context.Context, id int) (*models.Supplier, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	s, ok := repo.store.suppliers[id]
+	if !ok {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// Update memperbarui data supplier. Mengembalikan NotFoundError jika supplier tidak ditemukan.
+func (repo *SupplierRepository) Update(ctx context.Context, supplier *models.Supplier) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if _, ok := repo.store.suppliers[supplier.ID]; !ok {
+		return &models.NotFoundError{Resource: "supplier", ID: supplier.ID}
+	}
+	repo.store.suppliers[supplier.ID] = *supplier
+	return nil
+}
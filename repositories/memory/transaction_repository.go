@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"task-session-1/models"
+	"time"
+)
+
+// TransactionRepository adalah implementasi in-memory dari repositories.TransactionStore.
+type TransactionRepository struct {
+	store *Store
+}
+
+// NewTransactionRepository adalah konstruktor untuk membuat instance TransactionRepository in-memory.
+func NewTransactionRepository(store *Store) *TransactionRepository {
+	return &TransactionRepository{store: store}
+}
+
+// CreateTransaction membuat transaksi checkout dan mengurangi stok setiap produk.
+// Perubahan stok dihitung pada salinan terlebih dahulu dan baru diterapkan jika semua item valid,
+// sehingga kegagalan di tengah jalan tidak mengubah stok apa pun (setara rollback di database).
+func (repo *TransactionRepository) CreateTransaction(ctx context.Context, items []models.CheckoutItem) (*models.Transaction, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	totalAmount := 0
+	var details []models.TransactionDetail
+	stocks := make(map[int]int)
+	variantStocks := make(map[int]int)
+	var movements []models.StockMovement
+	var lowStock []models.LowStockEvent
+
+	for i, item := range items {
+		// Item merujuk produk dengan ID atau dengan barcode hasil scan.
+		var product models.Product
+		var ok bool
+		if item.Barcode != "" {
+			product, ok = repo.store.productByCode(func(p models.Product) bool { return p.Barcode == item.Barcode })
+		} else {
+			product, ok = repo.store.activeProduct(item.ProductID)
+		}
+		if !ok {
+			return nil, item.ProductNotFound()
+		}
+
+		// Produk yang punya varian dijual per varian: harga dan stok diambil dari varian.
+		var variant *models.ProductVariant
+		price := product.Price
+		stock, staged := stocks[product.ID]
+		if !staged {
+			stock = product.Stock
+		}
+		if item.VariantID != 0 {
+			v, ok := repo.store.activeVariant(item.VariantID)
+			if !ok || v.ProductID != product.ID {
+				return nil, &models.NotFoundError{Resource: "variant", ID: item.VariantID}
+			}
+			variant = &v
+			price = v.EffectivePrice(product.Price)
+			if stock, staged = variantStocks[v.ID]; !staged {
+				stock = v.Stock
+			}
+		} else if repo.store.hasVariants(product.ID) {
+			return nil, models.NewValidationError(fmt.Sprintf("items[%d].variant_id", i), "is required for products with variants")
+		}
+
+		if stock < item.Quantity {
+			return nil, &models.InsufficientStockError{
+				ProductID: product.ID,
+				VariantID: item.VariantID,
+				Requested: item.Quantity,
+				Available: stock,
+			}
+		}
+
+		subtotal := price * item.Quantity
+		totalAmount += subtotal
+
+		detail := models.TransactionDetail{
+			ProductID:   product.ID,
+			ProductName: product.Name,
+			Quantity:    item.Quantity,
+			Subtotal:    subtotal,
+		}
+		movement := models.StockMovement{
+			ProductID:  product.ID,
+			Delta:      -item.Quantity,
+			StockAfter: stock - item.Quantity,
+			Reason:     models.StockSale,
+		}
+		if variant != nil {
+			variantStocks[variant.ID] = stock - item.Quantity
+			detail.VariantID = &variant.ID
+			detail.VariantName = variant.Label()
+			movement.VariantID = &variant.ID
+		} else {
+			stocks[product.ID] = stock - item.Quantity
+		}
+		details = append(details, detail)
+		movements = append(movements, movement)
+
+		if !models.IsLowStock(stock, product.ReorderLevel) && models.IsLowStock(stock-item.Quantity, product.ReorderLevel) {
+			event := models.LowStockEvent{LowStockItem: models.LowStockItem{
+				ProductID:    product.ID,
+				Name:         product.Name,
+				SKU:          product.SKU,
+				CategoryID:   product.CategoryID,
+				Stock:        stock - item.Quantity,
+				ReorderLevel: product.ReorderLevel,
+				ReorderQty:   product.ReorderQty,
+			}}
+			if variant != nil {
+				event.VariantID = &variant.ID
+				event.VariantName = variant.Label()
+				event.SKU = variant.SKU
+			}
+			lowStock = append(lowStock, event)
+		}
+	}
+
+	// Semua item valid, terapkan perubahan stok ("commit").
+	for id, stock := range stocks {
+		product := repo.store.products[id]
+		product.Stock = stock
+		repo.store.products[id] = product
+	}
+	for id, stock := range variantStocks {
+		variant := repo.store.variants[id]
+		variant.Stock = stock
+		repo.store.variants[id] = variant
+	}
+
+	repo.store.lastTransactionID++
+	transaction := models.Transaction{
+		ID:          repo.store.lastTransactionID,
+		TotalAmount: totalAmount,
+		CreatedAt:   time.Now(),
+		Details:     details,
+	}
+	for i := range transaction.Details {
+		repo.store.lastDetailID++
+		transaction.Details[i].ID = repo.store.lastDetailID
+		transaction.Details[i].TransactionID = transaction.ID
+	}
+	repo.store.transactions = append(repo.store.transactions, transaction)
+	for _, m := range movements {
+		m.Reference = models.Reference("transaction", transaction.ID)
+		repo.store.recordMovement(m)
+	}
+
+	for i := range lowStock {
+		lowStock[i].TransactionID = transaction.ID
+		lowStock[i].OccurredAt = transaction.CreatedAt
+	}
+
+	result := transaction
+	result.Details = append([]models.TransactionDetail(nil), transaction.Details...)
+	result.LowStock = lowStock
+	return &result, nil
+}
+
+// GetReport menghitung total pendapatan, jumlah transaksi, dan produk terlaris
+// untuk transaksi yang dibuat pada rentang waktu [from, to).
+func (repo *TransactionRepository) GetReport(ctx context.Context, from, to time.Time) (*models.ReportResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	var totalRevenue, totalTransaksi int
+	qtyByProduct := make(map[int]int)
+	for _, t := range repo.store.transactions {
+		if t.CreatedAt.Before(from) || !t.CreatedAt.Before(to) {
+			continue
+		}
+		totalRevenue += t.TotalAmount
+		totalTransaksi++
+		for _, d := range t.Details {
+			qtyByProduct[d.ProductID] += d.Quantity
+		}
+	}
+
+	var bestID, bestQty int
+	for id, qty := range qtyByProduct {
+		if qty > bestQty || (qty == bestQty && id < bestID) {
+			bestID, bestQty = id, qty
+		}
+	}
+
+	// Produk yang sudah di-soft delete tetap ada di store, sehingga namanya tetap bisa ditampilkan.
+	var nama string
+	if bestQty > 0 {
+		nama = repo.store.products[bestID].Name
+	}
+
+	return &models.ReportResponse{
+		TotalRevenue:   totalRevenue,
+		TotalTransaksi: totalTransaksi,
+		ProdukTerlaris: models.ProdukTerlarisResponse{
+			Nama:       nama,
+			QtyTerjual: bestQty,
+		},
+	}, nil
+}
@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"task-session-1/models"
+	"time"
+)
+
+// VariantRepository adalah implementasi in-memory dari repositories.VariantStore.
+type VariantRepository struct {
+	store *Store
+}
+
+// NewVariantRepository adalah konstruktor untuk membuat instance VariantRepository in-memory.
+func NewVariantRepository(store *Store) *VariantRepository {
+	return &VariantRepository{store: store}
+}
+
+// ListByProducts mengambil varian aktif dari beberapa produk sekaligus,
+// dikelompokkan per ID produk dan diurutkan berdasarkan ID varian.
+func (repo *VariantRepository) ListByProducts(ctx context.Context, productIDs []int) (map[int][]models.ProductVariant, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	wanted := make(map[int]bool, len(productIDs))
+	for _, id := range productIDs {
+		wanted[id] = true
+	}
+
+	variants := make(map[int][]models.ProductVariant)
+	for id := range repo.store.variants {
+		v, ok := repo.store.activeVariant(id)
+		if ok && wanted[v.ProductID] {
+			variants[v.ProductID] = append(variants[v.ProductID], v)
+		}
+	}
+	for _, list := range variants {
+		sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	}
+	return variants, nil
+}
+
+// GetByID mengambil satu varian aktif berdasarkan ID.
+// Jika varian tidak ditemukan atau sudah dihapus, mengembalikan nil tanpa error.
+func (repo *VariantRepository) GetByID(ctx context.Context, id int) (*models.ProductVariant, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.store.mu.RLock()
+	defer repo.store.mu.RUnlock()
+
+	v, ok := repo.store.activeVariant(id)
+	if !ok {
+		return nil, nil
+	}
+	return &v, nil
+}
+
+// Create menyimpan varian baru dan mengisi ID-nya.
+func (repo *VariantRepository) Create(ctx context.Context, variant *models.ProductVariant) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	if _, ok := repo.store.products[variant.ProductID]; !ok {
+		return &models.NotFoundError{Resource: "product", ID: variant.ProductID}
+	}
+	if repo.taken(variant) {
+		return &models.ConflictError{Message: "variant sku or options are already used by another variant"}
+	}
+
+	repo.store.lastVariantID++
+	variant.ID = repo.store.lastVariantID
+	repo.save(*variant)
+	repo.store.recordMovement(models.StockMovement{
+		ProductID:  variant.ProductID,
+		VariantID:  &variant.ID,
+		Delta:      variant.Stock,
+		StockAfter: variant.Stock,
+		Reason:     models.StockInitial,
+	})
+
+	return nil
+}
+
+// Update memperbarui SKU, opsi, dan harga varian aktif berdasarkan ID; stok tidak ikut diubah.
+func (repo *VariantRepository) Update(ctx context.Context, variant *models.ProductVariant) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	stored, ok := repo.store.activeVariant(variant.ID)
+	if !ok {
+		return &models.NotFoundError{Resource: "variant", ID: variant.ID}
+	}
+
+	// Produk pemilik varian tidak bisa dipindah dan stok tidak ikut diubah, sama seperti UPDATE di repository SQL.
+	updated := *variant
+	updated.ProductID = stored.ProductID
+	if repo.taken(&updated) {
+		return &models.ConflictError{Message: "variant sku or options are already used by another variant"}
+	}
+	updated.Stock = stored.Stock
+	repo.save(updated)
+	variant.Stock = stored.Stock
+
+	return nil
+}
+
+// Delete menghapus varian secara soft delete (mengisi DeletedAt).
+func (repo *VariantRepository) Delete(ctx context.Context, id int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.store.mu.Lock()
+	defer repo.store.mu.Unlock()
+
+	v, ok := repo.store.activeVariant(id)
+	if !ok {
+		return &models.NotFoundError{Resource: "variant", ID: id}
+	}
+
+	now := time.Now()
+	v.DeletedAt = &now
+	repo.store.variants[id] = v
+
+	return nil
+}
+
+// taken mengembalikan true jika SKU varian sudah dipakai varian aktif lain, atau kombinasi opsinya
+// sudah dipakai varian aktif lain dari produk yang sama, seperti unique index di database.
+// Pemanggil harus sudah memegang lock store.
+func (repo *VariantRepository) taken(variant *models.ProductVariant) bool {
+	for id, other := range repo.store.variants {
+		if id == variant.ID || other.DeletedAt != nil {
+			continue
+		}
+		if variant.SKU != "" && other.SKU == variant.SKU {
+			return true
+		}
+		if other.ProductID == variant.ProductID && variant.SameOptions(&other) {
+			return true
+		}
+	}
+	return false
+}
+
+// save menyimpan salinan varian, termasuk salinan opsi dan harganya.
+// Pemanggil harus sudah memegang lock store.
+func (repo *VariantRepository) save(variant models.ProductVariant) {
+	variant.Options = maps.Clone(variant.Options)
+	if variant.Price != nil {
+		price := *variant.Price
+		variant.Price = &price
+	}
+	repo.store.variants[variant.ID] = variant
+}
@@ -1,98 +1,284 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
-	"errors"
-	// "errors" // Tidak digunakan, dikomentari.
+	"encoding/json"
+	"strings"
+	"task-session-1/database"
 	"task-session-1/models"
 )
 
 // ProductRepository adalah struct yang menyimpan koneksi database untuk operasi produk.
-// db adalah pointer ke koneksi database PostgreSQL.
+// db adalah koneksi database (PostgreSQL atau SQLite) beserta dialect-nya.
 type ProductRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
 // NewProductRepository adalah konstruktor untuk membuat instance ProductRepository.
 // Menerima koneksi database sebagai parameter dan mengembalikan pointer ke ProductRepository.
-func NewProductRepository(db *sql.DB) *ProductRepository {
+func NewProductRepository(db *database.DB) *ProductRepository {
 	return &ProductRepository{db: db}
 }
 
-// GetAll mengambil semua data produk dari database dengan JOIN ke tabel category.
-// Query SQL mengambil data produk dan nama product terkait.
-// Mengembalikan slice dari Product dan error jika ada.
-func (repo *ProductRepository) GetAll(name string) ([]models.Product, error) {
-	// Query SQL untuk mengambil semua produk dengan JOIN ke category.
-	query := `
-			SELECT
-			p.id,
-			p.name,
-			p.price,
-			p.stock,
-			p.category_id
-			FROM product p JOIN category c ON c.id = p.category_id`
-	
-	args := []interface{}{}
-	if name != "" {
-		query += " WHERE p.name ILIKE $1"
-		args = append(args, "%"+name+"%")
+// productSortColumns memetakan field pengurutan ke kolom tabel product.
+var productSortColumns = map[string]string{
+	"id":    "p.id",
+	"name":  "p.name",
+	"price": "p.price",
+	"stock": "p.stock",
+}
+
+// rowScanner adalah bagian dari *sql.Row dan *sql.Rows yang dipakai untuk membaca satu baris.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// productColumns mengembalikan daftar kolom SELECT untuk produk (alias tabel p).
+// Jika kategori diminta, kolom kategori (alias tabel c) ditambahkan sehingga query harus JOIN ke category.
+func productColumns(opts models.ProductOptions) string {
+	columns := "p.id, p.name, p.price, p.stock, p.category_id, COALESCE(p.sku, ''), COALESCE(p.barcode, ''), p.reorder_level, p.reorder_qty, p.deleted_at"
+	if opts.IncludeCategory {
+		columns += ", " + categoryColumns
+	}
+	return columns
+}
+
+// identifierConflict mengubah pelanggaran unique index SKU atau barcode menjadi ConflictError.
+// Service sudah memeriksa keunikan lebih dulu; ini menangani dua request yang menyimpan kode yang sama bersamaan.
+func identifierConflict(err error) error {
+	if database.IsUniqueViolation(err) {
+		return &models.ConflictError{Message: "sku or barcode is already used by another product"}
+	}
+	return err
+}
+
+// scanProduct membaca satu baris hasil query dengan kolom dari productColumns ke struct Product.
+func scanProduct(row rowScanner, opts models.ProductOptions) (models.Product, error) {
+	var p models.Product
+	dest := []interface{}{&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID, &p.SKU, &p.Barcode, &p.ReorderLevel, &p.ReorderQty, &p.DeletedAt}
+	if opts.IncludeCategory {
+		p.Category = &models.Category{}
+		dest = append(dest, categoryDest(p.Category)...)
+	}
+
+	if err := row.Scan(dest...); err != nil {
+		return models.Product{}, err
+	}
+	return p, nil
+}
+
+// GetAll mengambil satu halaman produk dari database dengan JOIN ke tabel category.
+// Jika filter.IncludeCategory aktif, kategori setiap produk diisi dari JOIN yang sama (tanpa query tambahan).
+// Filter nama, kategori, rentang harga, dan ketersediaan stok diterapkan di query,
+// lalu hasil diurutkan dan dipaginasi dengan offset atau cursor (keyset).
+// Mengembalikan Page berisi produk, cursor halaman berikutnya, dan total produk yang cocok dengan filter.
+func (repo *ProductRepository) GetAll(ctx context.Context, filter models.ProductFilter) (*models.Page[models.Product], error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	// Menyusun kondisi filter. Produk yang sudah dihapus disembunyikan kecuali diminta.
+	var q queryBuilder
+	if !filter.IncludeDeleted {
+		q.where("p.deleted_at IS NULL")
+	}
+	if filter.Name != "" {
+		q.where("p.name " + repo.db.Dialect.ILike() + " " + q.arg("%"+filter.Name+"%"))
+	}
+	if filter.CategoryID != 0 {
+		q.where("p.category_id = " + q.arg(filter.CategoryID))
+	}
+	if filter.MinPrice != nil {
+		q.where("p.price >= " + q.arg(*filter.MinPrice))
+	}
+	if filter.MaxPrice != nil {
+		q.where("p.price <= " + q.arg(*filter.MaxPrice))
+	}
+	if filter.InStock != nil {
+		if *filter.InStock {
+			q.where("p.stock > 0")
+		} else {
+			q.where("p.stock <= 0")
+		}
+	}
+
+	// Pencarian juga mencocokkan nama kategori, sehingga query hitung ikut JOIN ke category.
+	from := " FROM product p"
+	var score string
+	if filter.Query != "" {
+		from = " FROM product p JOIN category c ON c.id = p.category_id"
+		var cond string
+		cond, score = repo.search(&q, filter.Query)
+		q.where(cond)
+	}
+
+	// Menghitung total produk yang cocok dengan filter, sebelum cursor dan limit diterapkan.
+	page := &models.Page[models.Product]{Items: make([]models.Product, 0)}
+	countQuery := "SELECT COUNT(*)" + from + q.whereClause()
+	err := repo.db.QueryRowContext(ctx, repo.db.Rebind(countQuery), q.args...).Scan(&page.Total)
+	if err != nil {
+		return nil, err
+	}
+
+	// Query SQL untuk mengambil satu halaman produk dengan JOIN ke category.
+	// Pada pencarian, skor relevansi ikut diambil di kolom terakhir dan bisa dipakai untuk pengurutan.
+	column := productSortColumns[filter.Sort]
+	columns := productColumns(filter.ProductOptions)
+	if score != "" {
+		columns += ", " + score
+		if filter.Sort == "relevance" {
+			column = score
+		}
 	}
+	q.keyset(column, "p.id", filter.ListOptions)
+	query := "SELECT " + columns +
+		" FROM product p JOIN category c ON c.id = p.category_id" + q.whereClause() + q.orderLimit(column, "p.id", filter.ListOptions)
+
 	// Menjalankan query dan mendapatkan rows.
-	rows, err := repo.db.Query(query, args...)
+	rows, err := repo.db.QueryContext(ctx, repo.db.Rebind(query), q.args...)
 	if err != nil {
 		return nil, err
 	}
 	// Pastikan rows ditutup setelah selesai.
 	defer rows.Close()
 
-	// Membuat slice kosong untuk menyimpan hasil.
-	product := make([]models.Product, 0)
 	// Iterasi setiap row hasil query.
 	for rows.Next() {
-		var p models.Product
-		// Scan data dari row ke struct Product.
-		// Perhatian: Scan hanya mengambil field produk, tidak termasuk category name (ada kesalahan di query asli).
-		err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID)
+		// Scan data dari row ke struct Product, termasuk kategori dan skor relevansi jika diminta.
+		var row rowScanner = rows
+		var relevance int
+		if score != "" {
+			row = scoreScanner{rowScanner: rows, score: &relevance}
+		}
+		p, err := scanProduct(row, filter.ProductOptions)
 		if err != nil {
 			return nil, err
 		}
-		// Tambahkan produk ke slice.
-		product = append(product, p)
+		p.Score = relevance
+		// Tambahkan produk ke halaman.
+		page.Items = append(page.Items, p)
 	}
-	return product, nil
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Query mengambil satu data lebih banyak dari limit; jika ada, berarti masih ada halaman berikutnya.
+	if len(page.Items) > filter.Limit {
+		page.Items = page.Items[:filter.Limit]
+		page.NextCursor = filter.CursorFor(page.Items[filter.Limit-1])
+	}
+	return page, nil
 }
 
 // Create menyisipkan produk baru ke database.
 // Menggunakan INSERT dengan RETURNING id untuk mendapatkan ID yang dihasilkan.
+// Stok awal dicatat di ledger stock_movements dalam transaksi yang sama.
 // Mengembalikan error jika penyisipan gagal.
-func (repo *ProductRepository) Create(product *models.Product) error {
-	// Query INSERT untuk menyisipkan produk baru.
-	query := "INSERT INTO product (name, price, stock, category_id) VALUES ($1, $2, $3, $4) RETURNING id"
-	// Menjalankan query dan scan ID yang dihasilkan ke product.ID.
-	err := repo.db.QueryRow(query, product.Name, product.Price, product.Stock, product.CategoryID).Scan(&product.ID)
-	return err
+func (repo *ProductRepository) Create(ctx context.Context, product *models.Product) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := repo.insert(ctx, tx, product, models.StockInitial); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insert menyisipkan produk di dalam transaksi tx, mengisi ID-nya, dan mencatat stok awalnya di ledger.
+func (repo *ProductRepository) insert(ctx context.Context, tx *sql.Tx, product *models.Product, reason models.StockReason) error {
+	err := tx.QueryRowContext(ctx, repo.db.Rebind(insertProductQuery),
+		product.Name, product.Price, product.Stock, product.CategoryID, product.SKU, product.Barcode,
+		product.ReorderLevel, product.ReorderQty,
+	).Scan(&product.ID)
+	if err != nil {
+		return productWriteError(err)
+	}
+	return recordMovement(ctx, repo.db, tx, models.StockMovement{
+		ProductID:  product.ID,
+		Delta:      product.Stock,
+		StockAfter: product.Stock,
+		Reason:     reason,
+	})
+}
+
+// update menimpa kolom produk aktif selain stok di dalam transaksi tx, lalu mengisi product.Stock dengan stok
+// saat ini. Dipakai Import: stok produk yang sudah ada hanya bisa diubah lewat penyesuaian stok.
+// Mengembalikan NotFoundError jika produk tidak ditemukan atau sudah dihapus.
+func (repo *ProductRepository) update(ctx context.Context, tx *sql.Tx, product *models.Product) error {
+	err := tx.QueryRowContext(ctx, repo.db.Rebind(updateProductQuery),
+		product.Name, product.Price, product.CategoryID, product.SKU, product.Barcode,
+		product.ReorderLevel, product.ReorderQty, product.ID,
+	).Scan(&product.Stock)
+	if err == sql.ErrNoRows {
+		return &models.NotFoundError{Resource: "product", ID: product.ID}
+	}
+	return productWriteError(err)
+}
+
+// insertProductQuery menyisipkan produk baru dan mengembalikan ID-nya.
+// SKU dan barcode kosong disimpan sebagai NULL agar tidak bentrok dengan unique index.
+const insertProductQuery = `INSERT INTO product (name, price, stock, category_id, sku, barcode, reorder_level, reorder_qty)
+	VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), $7, $8) RETURNING id`
+
+// updateProductQuery menimpa semua kolom produk aktif berdasarkan ID kecuali stok, lalu mengembalikan stok saat ini.
+const updateProductQuery = `UPDATE product SET
+	name = $1, price = $2, category_id = $3, sku = NULLIF($4, ''), barcode = NULLIF($5, ''),
+	reorder_level = $6, reorder_qty = $7
+	WHERE id = $8 AND deleted_at IS NULL RETURNING stock`
+
+// productWriteError mengubah error INSERT atau UPDATE produk menjadi error domain:
+// kategori yang tidak ada ditolak oleh foreign key, dan SKU/barcode ganda oleh unique index.
+func productWriteError(err error) error {
+	if database.IsForeignKeyViolation(err) {
+		return models.NewValidationError("category_id", "category not found")
+	}
+	return identifierConflict(err)
 }
 
 // GetByID mengambil satu produk berdasarkan ID.
-// Jika produk tidak ditemukan, mengembalikan nil tanpa error.
+// Jika opts.IncludeCategory aktif, kategori produk ikut diambil dengan JOIN dalam query yang sama.
+// Jika produk tidak ditemukan (atau sudah dihapus dan opts.IncludeDeleted tidak aktif), mengembalikan nil tanpa error.
 // Mengembalikan pointer ke Product dan error jika ada.
-func (repo *ProductRepository) GetByID(id int) (*models.Product, error) {
-	// Query SELECT untuk mengambil produk berdasarkan ID.
-	// Perhatian: Ada kesalahan sintaks di query asli (stock FROM product p WHERE id=$1).
-	query := `
-			SELECT
-			p.id,
-			p.name,
-			p.price,
-			p.stock,
-			p.category_id
-			FROM product p WHERE id=$1
-			`
-	var p models.Product
+func (repo *ProductRepository) GetByID(ctx context.Context, id int, opts models.ProductOptions) (*models.Product, error) {
+	return repo.getBy(ctx, "p.id", id, opts)
+}
+
+// GetByBarcode mengambil produk aktif dengan barcode tertentu, atau nil jika tidak ada.
+func (repo *ProductRepository) GetByBarcode(ctx context.Context, barcode string, opts models.ProductOptions) (*models.Product, error) {
+	opts.IncludeDeleted = false
+	return repo.getBy(ctx, "p.barcode", barcode, opts)
+}
+
+// GetBySKU mengambil produk aktif dengan SKU tertentu, atau nil jika tidak ada.
+func (repo *ProductRepository) GetBySKU(ctx context.Context, sku string, opts models.ProductOptions) (*models.Product, error) {
+	opts.IncludeDeleted = false
+	return repo.getBy(ctx, "p.sku", sku, opts)
+}
+
+// getBy mengambil satu produk yang kolom column-nya bernilai value.
+// column harus berasal dari kode (bukan input client) dan bernilai unik, misalnya p.id atau p.barcode.
+func (repo *ProductRepository) getBy(ctx context.Context, column string, value interface{}, opts models.ProductOptions) (*models.Product, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	// Query SELECT untuk mengambil produk berdasarkan kolom yang diminta.
+	query := "SELECT " + productColumns(opts) + " FROM product p"
+	if opts.IncludeCategory {
+		query += " JOIN category c ON c.id = p.category_id"
+	}
+	query += " WHERE " + column + " = $1"
+	if !opts.IncludeDeleted {
+		query += " AND p.deleted_at IS NULL"
+	}
+
 	// Menjalankan query dan scan hasil ke struct Product.
-	// Perhatian: Scan tidak sesuai dengan field yang dipilih (kurang stock, tambah category_id).
-	err := repo.db.QueryRow(query, id).Scan(&p.ID, &p.Name, &p.Price, &p.Stock, &p.CategoryID)
+	p, err := scanProduct(repo.db.QueryRowContext(ctx, repo.db.Rebind(query), value), opts)
 
 	// Jika tidak ada row ditemukan, kembalikan nil.
 	if err == sql.ErrNoRows {
@@ -108,18 +294,90 @@ func (repo *ProductRepository) GetByID(id int) (*models.Product, error) {
 	return &p, nil
 }
 
-func (repo *ProductRepository) Update(product *models.Product) error {
-	query := `
-			UPDATE product SET 
-			name=$1, 
-			price=$2, 
-			stock=$3, 
-			category_id=$4 
-			WHERE id=$5`
-	result, err := repo.db.Exec(query, product.Name, product.Price, product.Stock, product.CategoryID, product.ID)
+// Update menimpa nama, harga, kategori, SKU, barcode, dan batas pemesanan ulang produk aktif berdasarkan ID.
+// Stok tidak ikut diubah (gunakan penyesuaian stok); product.Stock diisi dengan stok saat ini.
+// Mengembalikan NotFoundError jika produk tidak ditemukan.
+func (repo *ProductRepository) Update(ctx context.Context, product *models.Product) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	err := repo.db.QueryRowContext(ctx, repo.db.Rebind(updateProductQuery),
+		product.Name, product.Price, product.CategoryID, product.SKU, product.Barcode,
+		product.ReorderLevel, product.ReorderQty, product.ID,
+	).Scan(&product.Stock)
+	if err == sql.ErrNoRows {
+		return &models.NotFoundError{Resource: "product", ID: product.ID}
+	}
+	return productWriteError(err)
+}
+
+// Import menyimpan banyak produk dalam satu transaksi: produk dengan ID 0 disisipkan (ID-nya diisi) dan stok
+// awalnya dicatat di ledger dengan alasan import, dan produk lain diperbarui seperti Update tanpa mengubah stok.
+// Jika satu produk gagal, tidak ada produk yang tersimpan.
+func (repo *ProductRepository) Import(ctx context.Context, products []models.Product) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	for i := range products {
+		var err error
+		if products[i].ID == 0 {
+			err = repo.insert(ctx, tx, &products[i], models.StockImport)
+		} else {
+			err = repo.update(ctx, tx, &products[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Patch memperbarui hanya kolom produk yang diisi di patch dengan satu query UPDATE dinamis,
+// sehingga kolom lain (misalnya stok yang sedang berubah karena checkout) tidak ikut tertimpa.
+// Mengembalikan NotFoundError jika produk tidak ditemukan.
+func (repo *ProductRepository) Patch(ctx context.Context, id int, patch models.ProductPatch) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var q queryBuilder
+	var sets []string
+	if patch.Name != nil {
+		sets = append(sets, "name = "+q.arg(*patch.Name))
+	}
+	if patch.Price != nil {
+		sets = append(sets, "price = "+q.arg(*patch.Price))
+	}
+	if patch.CategoryID != nil {
+		sets = append(sets, "category_id = "+q.arg(*patch.CategoryID))
+	}
+	if patch.SKU != nil {
+		sets = append(sets, "sku = NULLIF("+q.arg(*patch.SKU)+", '')")
+	}
+	if patch.Barcode != nil {
+		sets = append(sets, "barcode = NULLIF("+q.arg(*patch.Barcode)+", '')")
+	}
+	if patch.ReorderLevel != nil {
+		sets = append(sets, "reorder_level = "+q.arg(*patch.ReorderLevel))
+	}
+	if patch.ReorderQty != nil {
+		sets = append(sets, "reorder_qty = "+q.arg(*patch.ReorderQty))
+	}
+	if len(sets) == 0 {
+		return nil
+	}
+
+	query := "UPDATE product SET " + strings.Join(sets, ", ") + " WHERE id = " + q.arg(id) + " AND deleted_at IS NULL"
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), q.args...)
+	if err != nil {
+		return productWriteError(err)
+	}
 
 	rows, err := result.RowsAffected()
 	if err != nil {
@@ -127,21 +385,24 @@ func (repo *ProductRepository) Update(product *models.Product) error {
 	}
 
 	if rows == 0 {
-		return errors.New("Product not found")
+		return &models.NotFoundError{Resource: "product", ID: id}
 	}
 
 	return nil
 }
 
-// Delete menghapus product berdasarkan ID.
-// Menggunakan DELETE dan memeriksa apakah ada baris yang terpengaruh.
-// Jika tidak ada baris yang terpengaruh, berarti product tidak ditemukan.
+// Delete menghapus product berdasarkan ID secara soft delete (mengisi deleted_at).
+// Baris produk tetap ada sehingga transaction_details dan laporan lama masih bisa menampilkan namanya.
+// Jika tidak ada baris yang terpengaruh, berarti product tidak ditemukan atau sudah dihapus.
 // Mengembalikan error jika delete gagal.
-func (repo *ProductRepository) Delete(id int) error {
-	// Query DELETE untuk menghapus product.
-	query := "DELETE FROM product WHERE id = $1"
+func (repo *ProductRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	// Query UPDATE untuk menandai product sebagai dihapus.
+	query := "UPDATE product SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
 	// Menjalankan query dan mendapatkan result.
-	result, err := repo.db.Exec(query, id)
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), id)
 	if err != nil {
 		return err
 	}
@@ -153,8 +414,86 @@ func (repo *ProductRepository) Delete(id int) error {
 
 	// Jika tidak ada baris yang terpengaruh, product tidak ditemukan.
 	if rows == 0 {
-		return errors.New("product not found!")
+		return &models.NotFoundError{Resource: "product", ID: id}
 	}
 
-	return err
-}
\ No newline at end of file
+	return nil
+}
+
+// Restore membatalkan soft delete product berdasarkan ID.
+// Mengembalikan NotFoundError jika tidak ada product terhapus dengan ID tersebut.
+func (repo *ProductRepository) Restore(ctx context.Context, id int) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE product SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL"
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), id)
+	if err != nil {
+		// SKU atau barcode produk mungkin sudah dipakai produk lain selama produk ini dihapus.
+		return identifierConflict(err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return &models.NotFoundError{Resource: "product", ID: id}
+	}
+
+	return nil
+}
+
+// ListLowStock mengambil produk aktif tanpa varian dan varian aktif yang stoknya sudah mencapai batas pemesanan
+// ulang produknya (reorder_level > 0), diurutkan berdasarkan produk lalu varian.
+func (repo *ProductRepository) ListLowStock(ctx context.Context, filter models.LowStockFilter) ([]models.LowStockItem, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var q queryBuilder
+	category := ""
+	if filter.CategoryID != 0 {
+		category = " AND p.category_id = " + q.arg(filter.CategoryID)
+	}
+
+	// Baris produk tanpa varian memakai 0 sebagai variant_id dan '' sebagai opsi agar kedua SELECT bisa di-UNION.
+	query := `
+		SELECT p.id, 0, p.name, '', COALESCE(p.sku, ''), p.category_id, p.stock, p.reorder_level, p.reorder_qty
+		FROM product p
+		WHERE p.deleted_at IS NULL AND p.reorder_level > 0 AND p.stock <= p.reorder_level` + category + `
+			AND NOT EXISTS (SELECT 1 FROM product_variant v WHERE v.product_id = p.id AND v.deleted_at IS NULL)
+		UNION ALL
+		SELECT p.id, v.id, p.name, v.options, COALESCE(v.sku, ''), p.category_id, v.stock, p.reorder_level, p.reorder_qty
+		FROM product_variant v
+		JOIN product p ON p.id = v.product_id
+		WHERE v.deleted_at IS NULL AND p.deleted_at IS NULL AND p.reorder_level > 0 AND v.stock <= p.reorder_level` + category + `
+		ORDER BY 1, 2`
+	rows, err := repo.db.QueryContext(ctx, repo.db.Rebind(query), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.LowStockItem, 0)
+	for rows.Next() {
+		var item models.LowStockItem
+		var variantID int
+		var options string
+		err := rows.Scan(&item.ProductID, &variantID, &item.Name, &options, &item.SKU, &item.CategoryID,
+			&item.Stock, &item.ReorderLevel, &item.ReorderQty)
+		if err != nil {
+			return nil, err
+		}
+		if variantID != 0 {
+			variant := models.ProductVariant{ID: variantID}
+			if err := json.Unmarshal([]byte(options), &variant.Options); err != nil {
+				return nil, err
+			}
+			item.VariantID = &variant.ID
+			item.VariantName = variant.Label()
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"database/sql/driver"
+	"strings"
+	"task-session-1/database"
+	"task-session-1/models"
+
+	"modernc.org/sqlite"
+)
+
+// init mendaftarkan fungsi SQL search_score(query, name, sku, category) ke driver SQLite untuk pencarian produk.
+// Skor dihitung dengan models.SearchScore, karena SQLite tidak punya full-text search dan trigram seperti PostgreSQL.
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("search_score", 4,
+		func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			return int64(models.SearchScore(sqliteText(args[0]), sqliteText(args[1]), sqliteText(args[2]), sqliteText(args[3]))), nil
+		})
+}
+
+// sqliteText mengubah argumen fungsi SQLite menjadi string; NULL menjadi string kosong.
+func sqliteText(v driver.Value) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
+
+// search mengembalikan kondisi WHERE dan ekspresi skor relevansi (bilangan bulat, makin besar makin relevan)
+// untuk pencarian produk di nama produk, SKU, dan nama kategori. Query harus JOIN ke category dengan alias c.
+//
+// PostgreSQL memakai kolom search_vector (tsvector dari nama dan SKU) dengan pencocokan awalan kata,
+// serta word_similarity dari pg_trgm untuk menoleransi salah ketik; keduanya didukung index GIN
+// (migrasi 0009_product_search). SQLite tidak punya keduanya, sehingga memakai fungsi search_score
+// yang didaftarkan init dengan aturan yang sama seperti storage in-memory.
+func (repo *ProductRepository) search(q *queryBuilder, query string) (cond, score string) {
+	if repo.db.Dialect != database.Postgres {
+		score = "search_score(" + q.arg(query) + ", p.name, COALESCE(p.sku, ''), c.name)"
+		return score + " > 0", score
+	}
+
+	// Kata query hanya berisi huruf dan angka, sehingga aman disusun menjadi tsquery awalan: "kopi:* & sus:*".
+	terms := models.SearchTerms(query)
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
+	}
+	tsquery := "to_tsquery('simple', " + q.arg(strings.Join(prefixes, " & ")) + ")"
+	text := q.arg(strings.Join(terms, " "))
+	sku := q.arg(strings.ToLower(query))
+
+	cond = "(p.search_vector @@ " + tsquery +
+		" OR " + text + " <% p.name OR " + text + " <% c.name OR LOWER(p.sku) = " + sku + ")"
+	score = "CAST(1000 * (ts_rank(p.search_vector, " + tsquery + ")" +
+		" + word_similarity(" + text + ", p.name) + 0.5 * word_similarity(" + text + ", c.name)" +
+		" + CASE WHEN LOWER(p.sku) = " + sku + " THEN 1 ELSE 0 END) AS INTEGER)"
+	return cond, score
+}
+
+// scoreScanner membaca kolom skor relevansi yang berada setelah kolom produk ke score.
+type scoreScanner struct {
+	rowScanner
+	score *int
+}
+
+func (s scoreScanner) Scan(dest ...interface{}) error {
+	return s.rowScanner.Scan(append(dest, s.score)...)
+}
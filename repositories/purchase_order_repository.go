@@ -0,0 +1,315 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"task-session-1/database"
+	"task-session-1/models"
+)
+
+// PurchaseOrderRepository adalah struct yang menyimpan koneksi database untuk purchase order dan barisnya.
+type PurchaseOrderRepository struct {
+	db *database.DB
+}
+
+// NewPurchaseOrderRepository adalah konstruktor untuk membuat instance PurchaseOrderRepository.
+func NewPurchaseOrderRepository(db *database.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: db}
+}
+
+// purchaseOrderSelect mengambil kolom purchase order (alias po) beserta nama suppliernya, sesuai urutan purchaseOrderDest.
+const purchaseOrderSelect = "SELECT po.id, po.supplier_id, s.name, po.status, po.note, po.created_at, po.updated_at" +
+	" FROM purchase_order po JOIN supplier s ON s.id = po.supplier_id"
+
+// purchaseOrderDest mengembalikan tujuan Scan untuk kolom dari purchaseOrderSelect.
+func purchaseOrderDest(po *models.PurchaseOrder) []interface{} {
+	return []interface{}{&po.ID, &po.SupplierID, &po.SupplierName, &po.Status, &po.Note, &po.CreatedAt, &po.UpdatedAt}
+}
+
+// queryer adalah bagian dari *sql.DB dan *sql.Tx yang dipakai untuk membaca beberapa baris,
+// sehingga query yang sama bisa dijalankan di dalam maupun di luar transaksi.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// loadItems mengisi Items, ExpectedCost, dan ReceivedCost setiap purchase order di orders dengan satu query.
+func loadItems(ctx context.Context, db *database.DB, q queryer, orders []models.PurchaseOrder) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	var qb queryBuilder
+	placeholders := make([]string, len(orders))
+	for i, po := range orders {
+		placeholders[i] = qb.arg(po.ID)
+	}
+	query := "SELECT i.id, i.purchase_order_id, i.product_id, i.variant_id, p.name, i.quantity, i.received_quantity, i.unit_cost" +
+		" FROM purchase_order_item i JOIN product p ON p.id = i.product_id" +
+		" WHERE i.purchase_order_id IN (" + strings.Join(placeholders, ", ") + ") ORDER BY i.id"
+	rows, err := q.QueryContext(ctx, db.Rebind(query), qb.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	items := make(map[int][]models.PurchaseOrderItem)
+	for rows.Next() {
+		var item models.PurchaseOrderItem
+		var orderID int
+		var variantID sql.NullInt64
+		err := rows.Scan(&item.ID, &orderID, &item.ProductID, &variantID, &item.ProductName,
+			&item.Quantity, &item.ReceivedQuantity, &item.UnitCost)
+		if err != nil {
+			return err
+		}
+		if variantID.Valid {
+			id := int(variantID.Int64)
+			item.VariantID = &id
+		}
+		items[orderID] = append(items[orderID], item)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for i := range orders {
+		orders[i].Items = items[orders[i].ID]
+		if orders[i].Items == nil {
+			orders[i].Items = make([]models.PurchaseOrderItem, 0)
+		}
+		orders[i].UpdateTotals()
+	}
+	return nil
+}
+
+// GetAll mengambil satu halaman purchase order beserta barisnya, difilter berdasarkan status dan supplier.
+func (repo *PurchaseOrderRepository) GetAll(ctx context.Context, filter models.PurchaseOrderFilter) (*models.Page[models.PurchaseOrder], error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var q queryBuilder
+	if statuses := filter.Statuses(); len(statuses) > 0 {
+		placeholders := make([]string, len(statuses))
+		for i, status := range statuses {
+			placeholders[i] = q.arg(status)
+		}
+		q.where("po.status IN (" + strings.Join(placeholders, ", ") + ")")
+	}
+	if filter.SupplierID != 0 {
+		q.where("po.supplier_id = " + q.arg(filter.SupplierID))
+	}
+
+	page := &models.Page[models.PurchaseOrder]{Items: make([]models.PurchaseOrder, 0)}
+	countQuery := "SELECT COUNT(*) FROM purchase_order po" + q.whereClause()
+	if err := repo.db.QueryRowContext(ctx, repo.db.Rebind(countQuery), q.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	q.keyset("po.id", "po.id", filter.ListOptions)
+	query := purchaseOrderSelect + q.whereClause() + q.orderLimit("po.id", "po.id", filter.ListOptions)
+	rows, err := repo.db.QueryContext(ctx, repo.db.Rebind(query), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var po models.PurchaseOrder
+		if err := rows.Scan(purchaseOrderDest(&po)...); err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, po)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Items) > filter.Limit {
+		page.Items = page.Items[:filter.Limit]
+		page.NextCursor = filter.CursorFor(page.Items[filter.Limit-1])
+	}
+	if err := loadItems(ctx, repo.db, repo.db, page.Items); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// Create menyisipkan purchase order berstatus ordered beserta semua barisnya dalam satu transaksi,
+// lalu mengisi ID, Status, CreatedAt, dan UpdatedAt purchase order serta ID setiap baris.
+func (repo *PurchaseOrderRepository) Create(ctx context.Context, po *models.PurchaseOrder) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	po.Status = models.PurchaseOrderOrdered
+	query := "INSERT INTO purchase_order (supplier_id, status, note) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at"
+	err = tx.QueryRowContext(ctx, repo.db.Rebind(query), po.SupplierID, po.Status, po.Note).Scan(&po.ID, &po.CreatedAt, &po.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	itemQuery := repo.db.Rebind("INSERT INTO purchase_order_item (purchase_order_id, product_id, variant_id, quantity, unit_cost)" +
+		" VALUES ($1, $2, $3, $4, $5) RETURNING id")
+	for i := range po.Items {
+		item := &po.Items[i]
+		item.ReceivedQuantity = 0
+		err := tx.QueryRowContext(ctx, itemQuery, po.ID, item.ProductID, item.VariantID, item.Quantity, item.UnitCost).Scan(&item.ID)
+		if err != nil {
+			return err
+		}
+	}
+	po.UpdateTotals()
+	return tx.Commit()
+}
+
+// GetByID mengambil satu purchase order beserta barisnya. Jika tidak ditemukan, mengembalikan nil tanpa error.
+func (repo *PurchaseOrderRepository) GetByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var po models.PurchaseOrder
+	err := repo.db.QueryRowContext(ctx, repo.db.Rebind(purchaseOrderSelect+" WHERE po.id = $1"), id).Scan(purchaseOrderDest(&po)...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	orders := []models.PurchaseOrder{po}
+	if err := loadItems(ctx, repo.db, repo.db, orders); err != nil {
+		return nil, err
+	}
+	return &orders[0], nil
+}
+
+// lockOpenOrder membaca status purchase order id di dalam transaksi dan di PostgreSQL mengunci barisnya,
+// sehingga penerimaan barang dan pembatalan untuk purchase order yang sama berjalan bergantian.
+// Mengembalikan NotFoundError jika purchase order tidak ada dan ConflictError jika statusnya tidak lagi open.
+func lockOpenOrder(ctx context.Context, db *database.DB, tx *sql.Tx, id int) error {
+	var status models.PurchaseOrderStatus
+	query := "SELECT status FROM purchase_order WHERE id = $1" + db.Dialect.ForUpdate()
+	err := tx.QueryRowContext(ctx, db.Rebind(query), id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return &models.NotFoundError{Resource: "purchase order", ID: id}
+	}
+	if err != nil {
+		return err
+	}
+	if !status.IsOpen() {
+		return models.NewPurchaseOrderClosedError(id, status)
+	}
+	return nil
+}
+
+// Receive menerima barang untuk purchase order id dalam satu transaksi: received_quantity setiap baris,
+// stok produk atau varian, ledger stok (alasan purchase_receipt dengan reference "purchase_order:<id>"),
+// dan status purchase order berubah bersama atau tidak sama sekali. Stok ditambah dengan stock = stock + quantity
+// sehingga tidak bentrok dengan checkout yang berjalan bersamaan.
+// Mengembalikan NotFoundError jika purchase order tidak ada, ConflictError jika purchase order tidak lagi open atau
+// produk/variannya sudah dihapus, dan ValidationError jika receipt tidak cocok dengan baris purchase order.
+func (repo *PurchaseOrderRepository) Receive(ctx context.Context, id int, receipt models.PurchaseOrderReceipt) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenOrder(ctx, repo.db, tx, id); err != nil {
+		return err
+	}
+
+	orders := []models.PurchaseOrder{{ID: id}}
+	if err := loadItems(ctx, repo.db, tx, orders); err != nil {
+		return err
+	}
+	po := &orders[0]
+
+	quantities, err := po.ReceiptQuantities(receipt)
+	if err != nil {
+		return err
+	}
+
+	reference := models.Reference("purchase_order", id)
+	itemQuery := repo.db.Rebind("UPDATE purchase_order_item SET received_quantity = received_quantity + $1 WHERE id = $2")
+	for i := range po.Items {
+		item := &po.Items[i]
+		quantity := quantities[i]
+		if quantity == 0 {
+			continue
+		}
+
+		if _, err := tx.ExecContext(ctx, itemQuery, quantity, item.ID); err != nil {
+			return err
+		}
+		item.ReceivedQuantity += quantity
+
+		table, resource, stockID := "product", "product", item.ProductID
+		if item.VariantID != nil {
+			table, resource, stockID = "product_variant", "variant", *item.VariantID
+		}
+		var stockAfter int
+		query := "UPDATE " + table + " SET stock = stock + $1 WHERE id = $2 AND deleted_at IS NULL RETURNING stock"
+		err := tx.QueryRowContext(ctx, repo.db.Rebind(query), quantity, stockID).Scan(&stockAfter)
+		if err == sql.ErrNoRows {
+			return &models.ConflictError{
+				Message: fmt.Sprintf("%s %d has been deleted", resource, stockID),
+				Details: map[string]interface{}{"item_id": item.ID},
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		err = recordMovement(ctx, repo.db, tx, models.StockMovement{
+			ProductID:  item.ProductID,
+			VariantID:  item.VariantID,
+			Delta:      quantity,
+			StockAfter: stockAfter,
+			Reason:     models.StockPurchaseReceipt,
+			Reference:  reference,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	query := "UPDATE purchase_order SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
+	if _, err := tx.ExecContext(ctx, repo.db.Rebind(query), po.ReceivedStatus(), id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Cancel membatalkan purchase order yang masih open. Barang yang sudah diterima tetap tercatat.
+// Mengembalikan NotFoundError jika purchase order tidak ada dan ConflictError jika statusnya tidak lagi open.
+func (repo *PurchaseOrderRepository) Cancel(ctx context.Context, id int) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenOrder(ctx, repo.db, tx, id); err != nil {
+		return err
+	}
+
+	query := "UPDATE purchase_order SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
+	if _, err := tx.ExecContext(ctx, repo.db.Rebind(query), models.PurchaseOrderCancelled, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"strconv"
+	"strings"
+	"task-session-1/models"
+)
+
+// queryBuilder membantu menyusun query dinamis (klausa WHERE atau SET) dengan placeholder $n.
+// Placeholder dikonversi ke format dialect dengan DB.Rebind sebelum query dijalankan.
+type queryBuilder struct {
+	conds []string
+	args  []interface{}
+}
+
+// arg menambahkan argumen query dan mengembalikan placeholder-nya, misalnya "$3".
+func (q *queryBuilder) arg(v interface{}) string {
+	q.args = append(q.args, v)
+	return "$" + strconv.Itoa(len(q.args))
+}
+
+// where menambahkan satu kondisi yang digabung dengan AND.
+func (q *queryBuilder) where(cond string) {
+	q.conds = append(q.conds, cond)
+}
+
+// whereClause mengembalikan klausa " WHERE ..." atau string kosong jika tidak ada kondisi.
+func (q *queryBuilder) whereClause() string {
+	if len(q.conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conds, " AND ")
+}
+
+// keyset menambahkan kondisi paginasi cursor: hanya data setelah posisi cursor
+// menurut urutan (column, idColumn) yang diambil.
+func (q *queryBuilder) keyset(column, idColumn string, opts models.ListOptions) {
+	if opts.Cursor == nil {
+		return
+	}
+
+	op := ">"
+	if opts.Desc {
+		op = "<"
+	}
+	value := q.arg(opts.Cursor.Value)
+	id := q.arg(opts.Cursor.ID)
+	if column == idColumn {
+		q.where(idColumn + " " + op + " " + id)
+		return
+	}
+	q.where("(" + column + " " + op + " " + value + " OR (" + column + " = " + value + " AND " + idColumn + " " + op + " " + id + "))")
+}
+
+// orderLimit mengembalikan klausa ORDER BY, LIMIT, dan OFFSET.
+// Limit diambil satu lebih banyak agar bisa diketahui apakah masih ada halaman berikutnya.
+func (q *queryBuilder) orderLimit(column, idColumn string, opts models.ListOptions) string {
+	dir := " ASC"
+	if opts.Desc {
+		dir = " DESC"
+	}
+
+	order := " ORDER BY " + column + dir
+	if column != idColumn {
+		order += ", " + idColumn + dir
+	}
+	return order + " LIMIT " + q.arg(opts.Limit+1) + " OFFSET " + q.arg(opts.Offset)
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"task-session-1/models"
+	"time"
+)
+
+// CategoryStore adalah kontrak penyimpanan data kategori yang dipakai oleh service.
+// Implementasinya bisa berupa database SQL (CategoryRepository) maupun penyimpanan in-memory.
+// Semua method menerima context agar operasi berhenti saat request dibatalkan atau melewati batas waktu.
+// Delete melakukan soft delete (mengisi deleted_at) dan Restore membatalkannya.
+// Data yang sudah dihapus tidak ikut dibaca kecuali IncludeDeleted diaktifkan.
+// Delete kategori menjalankan CategoryDeletePolicy yang sudah divalidasi dalam satu transaksi
+// dan mengembalikan jumlah produk yang dipindahkan atau ikut dihapus.
+type CategoryStore interface {
+	GetAll(ctx context.Context, filter models.CategoryFilter) (*models.Page[models.Category], error)
+	Create(ctx context.Context, category *models.Category) error
+	GetByID(ctx context.Context, id int, opts models.CategoryOptions) (*models.Category, error)
+	Update(ctx context.Context, category *models.Category) error
+	Patch(ctx context.Context, id int, patch models.CategoryPatch) error
+	Delete(ctx context.Context, id int, policy models.CategoryDeletePolicy) (int, error)
+	Restore(ctx context.Context, id int) error
+}
+
+// ProductStore adalah kontrak penyimpanan data produk yang dipakai oleh service.
+// GetAll menerima filter yang sudah divalidasi (lihat ProductFilter.Validate).
+// GetByBarcode dan GetBySKU hanya mencari di antara produk aktif, karena kode tersebut hanya unik untuk produk aktif.
+// Create, Update, Patch, Import, dan Restore mengembalikan ConflictError jika SKU atau barcode sudah dipakai produk aktif lain.
+// Import wajib atomik: produk baru (ID 0) disisipkan dan produk lain diperbarui tanpa mengubah stoknya, atau tidak ada
+// yang berubah sama sekali.
+// Update dan Patch tidak mengubah stok; stok diubah lewat StockMovementStore.Adjust.
+// ListLowStock mengembalikan produk tanpa varian dan varian yang stoknya sudah mencapai batas pemesanan ulang produknya.
+type ProductStore interface {
+	GetAll(ctx context.Context, filter models.ProductFilter) (*models.Page[models.Product], error)
+	Create(ctx context.Context, product *models.Product) error
+	GetByID(ctx context.Context, id int, opts models.ProductOptions) (*models.Product, error)
+	GetByBarcode(ctx context.Context, barcode string, opts models.ProductOptions) (*models.Product, error)
+	GetBySKU(ctx context.Context, sku string, opts models.ProductOptions) (*models.Product, error)
+	Update(ctx context.Context, product *models.Product) error
+	Import(ctx context.Context, products []models.Product) error
+	Patch(ctx context.Context, id int, patch models.ProductPatch) error
+	Delete(ctx context.Context, id int) error
+	Restore(ctx context.Context, id int) error
+	ListLowStock(ctx context.Context, filter models.LowStockFilter) ([]models.LowStockItem, error)
+}
+
+// VariantStore adalah kontrak penyimpanan varian produk.
+// Semua method hanya membaca varian aktif; Delete melakukan soft delete agar riwayat transaksi tetap utuh.
+// Create dan Update mengembalikan ConflictError jika SKU atau kombinasi opsi sudah dipakai varian aktif lain.
+type VariantStore interface {
+	ListByProducts(ctx context.Context, productIDs []int) (map[int][]models.ProductVariant, error)
+	GetByID(ctx context.Context, id int) (*models.ProductVariant, error)
+	Create(ctx context.Context, variant *models.ProductVariant) error
+	Update(ctx context.Context, variant *models.ProductVariant) error
+	Delete(ctx context.Context, id int) error
+}
+
+// TransactionStore adalah kontrak penyimpanan transaksi checkout dan laporan penjualan.
+// CreateTransaction wajib atomik: jika satu item gagal, stok semua produk tidak berubah.
+// Item dengan varian mengurangi stok varian, bukan stok produk. Transaction.LowStock diisi dengan produk atau varian
+// yang stoknya turun melewati batas pemesanan ulang karena transaksi tersebut (lihat models.IsLowStock).
+// GetReport menghitung transaksi yang dibuat pada rentang waktu [from, to).
+type TransactionStore interface {
+	CreateTransaction(ctx context.Context, items []models.CheckoutItem) (*models.Transaction, error)
+	GetReport(ctx context.Context, from, to time.Time) (*models.ReportResponse, error)
+}
+
+// StockMovementStore adalah kontrak ledger stok.
+// Pergerakan stok ditulis oleh ProductStore, VariantStore, TransactionStore, PurchaseOrderStore, StockCountStore,
+// dan Adjust secara atomik bersama perubahan stoknya, sehingga stok produk atau varian selalu sama dengan jumlah
+// delta di ledger.
+// Adjust wajib atomik (stock = stock + delta) dan menolak stok negatif dengan InsufficientStockError.
+type StockMovementStore interface {
+	Adjust(ctx context.Context, movement *models.StockMovement) error
+	ListByProduct(ctx context.Context, filter models.StockMovementFilter) (*models.Page[models.StockMovement], error)
+	Reconcile(ctx context.Context) ([]models.StockDiscrepancy, error)
+}
+
+// SupplierStore adalah kontrak penyimpanan data supplier. Supplier tidak bisa dihapus karena dirujuk purchase order.
+type SupplierStore interface {
+	GetAll(ctx context.Context, filter models.SupplierFilter) (*models.Page[models.Supplier], error)
+	Create(ctx context.Context, supplier *models.Supplier) error
+	GetByID(ctx context.Context, id int) (*models.Supplier, error)
+	Update(ctx context.Context, supplier *models.Supplier) error
+}
+
+// PurchaseOrderStore adalah kontrak penyimpanan purchase order beserta barisnya.
+// Create wajib atomik dan selalu menyimpan purchase order dengan status ordered.
+// Receive wajib atomik: received_quantity baris, stok produk atau varian (stock = stock + quantity), ledger stok
+// dengan alasan purchase_receipt, dan status purchase order berubah bersama, atau tidak ada yang berubah.
+// Receive dan Cancel mengembalikan ConflictError jika purchase order sudah received atau cancelled.
+type PurchaseOrderStore interface {
+	GetAll(ctx context.Context, filter models.PurchaseOrderFilter) (*models.Page[models.PurchaseOrder], error)
+	Create(ctx context.Context, po *models.PurchaseOrder) error
+	GetByID(ctx context.Context, id int) (*models.PurchaseOrder, error)
+	Receive(ctx context.Context, id int, receipt models.PurchaseOrderReceipt) error
+	Cancel(ctx context.Context, id int) error
+}
+
+// StockCountStore adalah kontrak penyimpanan sesi stock opname beserta barisnya.
+// Create wajib atomik: sesi open dan salinan stok produk tanpa varian dan varian aktif disimpan bersama.
+// Hanya satu sesi yang boleh open; Create mengembalikan ConflictError jika masih ada sesi open.
+// Post wajib atomik: selisih hasil hitung ditambahkan ke stok produk atau varian, dicatat di ledger stok dengan alasan
+// stock_opname, dan status sesi menjadi posted bersama, atau tidak ada yang berubah.
+// RecordCounts, Post, dan Cancel mengembalikan ConflictError jika sesi sudah posted atau cancelled.
+type StockCountStore interface {
+	GetAll(ctx context.Context, filter models.StockCountFilter) (*models.Page[models.StockCount], error)
+	Create(ctx context.Context, count *models.StockCount) error
+	GetByID(ctx context.Context, id int) (*models.StockCount, error)
+	RecordCounts(ctx context.Context, id int, counts map[int]int) error
+	Post(ctx context.Context, id int) error
+	Cancel(ctx context.Context, id int) error
+}
+
+// Memastikan repository SQL memenuhi interface saat kompilasi.
+var (
+	_ CategoryStore      = (*CategoryRepository)(nil)
+	_ ProductStore       = (*ProductRepository)(nil)
+	_ PurchaseOrderStore = (*PurchaseOrderRepository)(nil)
+	_ StockCountStore    = (*StockCountRepository)(nil)
+	_ StockMovementStore = (*StockMovementRepository)(nil)
+	_ SupplierStore      = (*SupplierRepository)(nil)
+	_ TransactionStore   = (*TransactionRepository)(nil)
+	_ VariantStore       = (*VariantRepository)(nil)
+)
@@ -0,0 +1,362 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"maps"
+	"slices"
+	"task-session-1/database"
+	"task-session-1/models"
+)
+
+// StockCountRepository adalah struct yang menyimpan koneksi database untuk sesi stock opname dan barisnya.
+type StockCountRepository struct {
+	db *database.DB
+}
+
+// NewStockCountRepository adalah konstruktor untuk membuat instance StockCountRepository.
+func NewStockCountRepository(db *database.DB) *StockCountRepository {
+	return &StockCountRepository{db: db}
+}
+
+// stockCountColumns adalah kolom SELECT untuk sesi stock opname, sesuai urutan Scan di scanStockCount.
+const stockCountColumns = "id, status, category_id, note, created_at, updated_at, posted_at"
+
+// scanStockCount membaca satu baris kolom stockCountColumns.
+func scanStockCount(row rowScanner) (models.StockCount, error) {
+	var c models.StockCount
+	var categoryID sql.NullInt64
+	var postedAt sql.NullTime
+	if err := row.Scan(&c.ID, &c.Status, &categoryID, &c.Note, &c.CreatedAt, &c.UpdatedAt, &postedAt); err != nil {
+		return c, err
+	}
+	if categoryID.Valid {
+		id := int(categoryID.Int64)
+		c.CategoryID = &id
+	}
+	if postedAt.Valid {
+		c.PostedAt = &postedAt.Time
+	}
+	return c, nil
+}
+
+// GetAll mengambil satu halaman sesi stock opname tanpa barisnya, difilter berdasarkan status.
+func (repo *StockCountRepository) GetAll(ctx context.Context, filter models.StockCountFilter) (*models.Page[models.StockCount], error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var q queryBuilder
+	if filter.Status != "" {
+		q.where("status = " + q.arg(filter.Status))
+	}
+
+	page := &models.Page[models.StockCount]{Items: make([]models.StockCount, 0)}
+	countQuery := "SELECT COUNT(*) FROM stock_count" + q.whereClause()
+	if err := repo.db.QueryRowContext(ctx, repo.db.Rebind(countQuery), q.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	q.keyset("id", "id", filter.ListOptions)
+	query := "SELECT " + stockCountColumns + " FROM stock_count" + q.whereClause() + q.orderLimit("id", "id", filter.ListOptions)
+	rows, err := repo.db.QueryContext(ctx, repo.db.Rebind(query), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c, err := scanStockCount(rows)
+		if err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Items) > filter.Limit {
+		page.Items = page.Items[:filter.Limit]
+		page.NextCursor = filter.CursorFor(page.Items[filter.Limit-1])
+	}
+	return page, nil
+}
+
+// Create membuka sesi stock opname baru dalam satu transaksi: sesi berstatus open disisipkan bersama salinan stok
+// setiap produk aktif tanpa varian dan setiap varian aktif (hanya kategori count.CategoryID jika diisi).
+// ID, Status, CreatedAt, dan UpdatedAt sesi diisi dari baris yang disimpan.
+// Mengembalikan ConflictError jika masih ada sesi lain yang open.
+func (repo *StockCountRepository) Create(ctx context.Context, count *models.StockCount) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var openID int
+	err = tx.QueryRowContext(ctx, repo.db.Rebind("SELECT id FROM stock_count WHERE status = $1"), models.StockCountOpen).Scan(&openID)
+	if err == nil {
+		return models.NewStockCountOpenError(openID)
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+
+	count.Status = models.StockCountOpen
+	query := "INSERT INTO stock_count (status, category_id, note) VALUES ($1, $2, $3) RETURNING id, created_at, updated_at"
+	err = tx.QueryRowContext(ctx, repo.db.Rebind(query), count.Status, count.CategoryID, count.Note).
+		Scan(&count.ID, &count.CreatedAt, &count.UpdatedAt)
+	if database.IsUniqueViolation(err) {
+		// Sesi lain dibuka bersamaan dan lebih dulu disimpan (unique index idx_stock_count_open).
+		return &models.ConflictError{Message: "another stock count is already open"}
+	}
+	if err != nil {
+		return err
+	}
+
+	var q queryBuilder
+	countID := q.arg(count.ID)
+	category := ""
+	if count.CategoryID != nil {
+		category = " AND p.category_id = " + q.arg(*count.CategoryID)
+	}
+
+	// CAST diperlukan agar PostgreSQL tahu tipe parameter ID sesi di dalam UNION.
+	snapshot := `
+		INSERT INTO stock_count_item (stock_count_id, product_id, variant_id, system_quantity)
+		SELECT CAST(` + countID + ` AS INTEGER), p.id, NULL, p.stock
+		FROM product p
+		WHERE p.deleted_at IS NULL` + category + `
+			AND NOT EXISTS (SELECT 1 FROM product_variant v WHERE v.product_id = p.id AND v.deleted_at IS NULL)
+		UNION ALL
+		SELECT CAST(` + countID + ` AS INTEGER), p.id, v.id, v.stock
+		FROM product_variant v
+		JOIN product p ON p.id = v.product_id
+		WHERE v.deleted_at IS NULL AND p.deleted_at IS NULL` + category + `
+		ORDER BY 2, 3`
+	if _, err := tx.ExecContext(ctx, repo.db.Rebind(snapshot), q.args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetByID mengambil satu sesi stock opname beserta barisnya dan ringkasannya.
+// Jika tidak ditemukan, mengembalikan nil tanpa error.
+func (repo *StockCountRepository) GetByID(ctx context.Context, id int) (*models.StockCount, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + stockCountColumns + " FROM stock_count WHERE id = $1"
+	count, err := scanStockCount(repo.db.QueryRowContext(ctx, repo.db.Rebind(query), id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if count.Items, err = loadCountItems(ctx, repo.db, repo.db, id, false); err != nil {
+		return nil, err
+	}
+	count.UpdateSummary()
+	return &count, nil
+}
+
+// loadCountItems mengambil baris sesi stock opname countID beserta nama produk, nama varian, dan SKU-nya,
+// atau hanya baris yang sudah dihitung jika countedOnly.
+func loadCountItems(ctx context.Context, db *database.DB, q queryer, countID int, countedOnly bool) ([]models.StockCountItem, error) {
+	query := `
+		SELECT i.id, i.product_id, i.variant_id, p.name, COALESCE(v.options, ''),
+			CASE WHEN i.variant_id IS NULL THEN COALESCE(p.sku, '') ELSE COALESCE(v.sku, '') END,
+			i.system_quantity, i.counted_quantity
+		FROM stock_count_item i
+		JOIN product p ON p.id = i.product_id
+		LEFT JOIN product_variant v ON v.id = i.variant_id
+		WHERE i.stock_count_id = $1`
+	if countedOnly {
+		query += " AND i.counted_quantity IS NOT NULL"
+	}
+	rows, err := q.QueryContext(ctx, db.Rebind(query+" ORDER BY i.id"), countID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]models.StockCountItem, 0)
+	for rows.Next() {
+		var item models.StockCountItem
+		var variantID, counted sql.NullInt64
+		var options string
+		err := rows.Scan(&item.ID, &item.ProductID, &variantID, &item.Name, &options, &item.SKU,
+			&item.SystemQuantity, &counted)
+		if err != nil {
+			return nil, err
+		}
+		if variantID.Valid {
+			variant := models.ProductVariant{ID: int(variantID.Int64)}
+			if err := json.Unmarshal([]byte(options), &variant.Options); err != nil {
+				return nil, err
+			}
+			item.VariantID = &variant.ID
+			item.VariantName = variant.Label()
+		}
+		if counted.Valid {
+			quantity := int(counted.Int64)
+			item.CountedQuantity = &quantity
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// lockOpenCount membaca status sesi stock opname id di dalam transaksi dan di PostgreSQL mengunci barisnya,
+// sehingga pencatatan hasil hitung, posting, dan pembatalan sesi yang sama berjalan bergantian.
+// Mengembalikan NotFoundError jika sesi tidak ada dan ConflictError jika statusnya tidak lagi open.
+func lockOpenCount(ctx context.Context, db *database.DB, tx *sql.Tx, id int) error {
+	var status models.StockCountStatus
+	query := "SELECT status FROM stock_count WHERE id = $1" + db.Dialect.ForUpdate()
+	err := tx.QueryRowContext(ctx, db.Rebind(query), id).Scan(&status)
+	if err == sql.ErrNoRows {
+		return &models.NotFoundError{Resource: "stock count", ID: id}
+	}
+	if err != nil {
+		return err
+	}
+	if status != models.StockCountOpen {
+		return models.NewStockCountClosedError(id, status)
+	}
+	return nil
+}
+
+// RecordCounts menyimpan hasil hitung fisik sesi stock opname id, dengan key ID baris dan nilai jumlah yang
+// dihitung, dalam satu transaksi. Hasil hitung sebelumnya ditimpa.
+// Mengembalikan NotFoundError jika sesi tidak ada dan ConflictError jika sesi tidak lagi open.
+func (repo *StockCountRepository) RecordCounts(ctx context.Context, id int, counts map[int]int) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenCount(ctx, repo.db, tx, id); err != nil {
+		return err
+	}
+
+	itemQuery := repo.db.Rebind("UPDATE stock_count_item SET counted_quantity = $1 WHERE id = $2 AND stock_count_id = $3")
+	for _, itemID := range slices.Sorted(maps.Keys(counts)) {
+		if _, err := tx.ExecContext(ctx, itemQuery, counts[itemID], itemID, id); err != nil {
+			return err
+		}
+	}
+
+	query := "UPDATE stock_count SET updated_at = CURRENT_TIMESTAMP WHERE id = $1"
+	if _, err := tx.ExecContext(ctx, repo.db.Rebind(query), id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Post memposting sesi stock opname id dalam satu transaksi: selisih setiap baris yang sudah dihitung
+// (counted_quantity - system_quantity) ditambahkan ke stok produk atau varian saat ini dan dicatat di ledger stok
+// dengan alasan stock_opname dan reference "stock_count:<id>", lalu status sesi menjadi posted.
+// Stok setiap baris dikunci sebelum diubah, sehingga checkout dan penyesuaian stok yang berjalan bersamaan
+// menunggu posting selesai, dan sesi yang sedang diposting menolak hasil hitung baru.
+// Produk atau varian yang sudah dihapus sejak sesi dibuka dilewati.
+// Mengembalikan NotFoundError jika sesi tidak ada, ConflictError jika sesi tidak lagi open, dan
+// InsufficientStockError jika selisih akan membuat stok negatif.
+func (repo *StockCountRepository) Post(ctx context.Context, id int) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenCount(ctx, repo.db, tx, id); err != nil {
+		return err
+	}
+
+	items, err := loadCountItems(ctx, repo.db, tx, id, true)
+	if err != nil {
+		return err
+	}
+
+	reference := models.Reference("stock_count", id)
+	for _, item := range items {
+		variance := *item.CountedQuantity - item.SystemQuantity
+		if variance == 0 {
+			continue
+		}
+
+		table, stockID, variantID := "product", item.ProductID, 0
+		if item.VariantID != nil {
+			table, stockID, variantID = "product_variant", *item.VariantID, *item.VariantID
+		}
+		stock, err := lockStock(ctx, repo.db, tx, table, stockID)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		stockAfter := stock + variance
+		if stockAfter < 0 {
+			return &models.InsufficientStockError{ProductID: item.ProductID, VariantID: variantID, Requested: -variance, Available: stock}
+		}
+		query := "UPDATE " + table + " SET stock = $1 WHERE id = $2"
+		if _, err := tx.ExecContext(ctx, repo.db.Rebind(query), stockAfter, stockID); err != nil {
+			return err
+		}
+
+		err = recordMovement(ctx, repo.db, tx, models.StockMovement{
+			ProductID:  item.ProductID,
+			VariantID:  item.VariantID,
+			Delta:      variance,
+			StockAfter: stockAfter,
+			Reason:     models.StockOpname,
+			Reference:  reference,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	query := "UPDATE stock_count SET status = $1, posted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
+	if _, err := tx.ExecContext(ctx, repo.db.Rebind(query), models.StockCountPosted, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Cancel membatalkan sesi stock opname yang masih open tanpa mengubah stok.
+// Mengembalikan NotFoundError jika sesi tidak ada dan ConflictError jika statusnya tidak lagi open.
+func (repo *StockCountRepository) Cancel(ctx context.Context, id int) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockOpenCount(ctx, repo.db, tx, id); err != nil {
+		return err
+	}
+
+	query := "UPDATE stock_count SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2"
+	if _, err := tx.ExecContext(ctx, repo.db.Rebind(query), models.StockCountCancelled, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"task-session-1/database"
+	"task-session-1/models"
+)
+
+// StockMovementRepository adalah struct yang menyimpan koneksi database untuk ledger stok.
+// Selain Adjust, pergerakan stok ditulis oleh repository yang mengubah stok (produk, varian, transaksi)
+// dengan recordMovement, di dalam transaksi yang sama dengan perubahan stoknya.
+type StockMovementRepository struct {
+	db *database.DB
+}
+
+// NewStockMovementRepository adalah konstruktor untuk membuat instance StockMovementRepository.
+func NewStockMovementRepository(db *database.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: db}
+}
+
+// insertMovementQuery menyisipkan satu baris ledger stok. Reference kosong disimpan sebagai NULL.
+const insertMovementQuery = `INSERT INTO stock_movements (product_id, variant_id, delta, stock_after, reason, reference)
+	VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))`
+
+// recordMovement mencatat pergerakan stok di dalam transaksi tx. Delta 0 tidak dicatat.
+func recordMovement(ctx context.Context, db *database.DB, tx *sql.Tx, m models.StockMovement) error {
+	if m.Delta == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, db.Rebind(insertMovementQuery),
+		m.ProductID, m.VariantID, m.Delta, m.StockAfter, m.Reason, m.Reference)
+	return err
+}
+
+// lockStock membaca stok baris aktif id di tabel table (product atau product_variant) di dalam transaksi,
+// dan di PostgreSQL mengunci barisnya sampai transaksi selesai agar delta yang dicatat tepat.
+// Mengembalikan sql.ErrNoRows jika baris tidak ada atau sudah dihapus.
+func lockStock(ctx context.Context, db *database.DB, tx *sql.Tx, table string, id int) (int, error) {
+	var stock int
+	query := "SELECT stock FROM " + table + " WHERE id = $1 AND deleted_at IS NULL" + db.Dialect.ForUpdate()
+	err := tx.QueryRowContext(ctx, db.Rebind(query), id).Scan(&stock)
+	return stock, err
+}
+
+// Adjust menambahkan m.Delta ke stok produk m.ProductID, atau ke stok varian m.VariantID, dengan satu UPDATE
+// (stock = stock + delta) yang tidak pernah membuat stok negatif, lalu mencatatnya di ledger dalam transaksi
+// yang sama. m.ID, m.StockAfter, dan m.CreatedAt diisi dari baris ledger yang disimpan.
+// Mengembalikan NotFoundError jika produk atau varian tidak ditemukan, dan InsufficientStockError jika
+// stok tidak cukup untuk delta negatif.
+func (repo *StockMovementRepository) Adjust(ctx context.Context, m *models.StockMovement) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	table, resource, id, variantID := "product", "product", m.ProductID, 0
+	if m.VariantID != nil {
+		table, resource, id, variantID = "product_variant", "variant", *m.VariantID, *m.VariantID
+	}
+
+	query := "UPDATE " + table + " SET stock = stock + $1 WHERE id = $2 AND deleted_at IS NULL AND stock + $1 >= 0 RETURNING stock"
+	err = tx.QueryRowContext(ctx, repo.db.Rebind(query), m.Delta, id).Scan(&m.StockAfter)
+	if err == sql.ErrNoRows {
+		// Tidak ada baris yang berubah: bedakan data yang tidak ada dengan stok yang tidak cukup.
+		stock, err := lockStock(ctx, repo.db, tx, table, id)
+		if err == sql.ErrNoRows {
+			return &models.NotFoundError{Resource: resource, ID: id}
+		}
+		if err != nil {
+			return err
+		}
+		return &models.InsufficientStockError{ProductID: m.ProductID, VariantID: variantID, Requested: -m.Delta, Available: stock}
+	}
+	if err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, repo.db.Rebind(insertMovementQuery+" RETURNING id, created_at"),
+		m.ProductID, m.VariantID, m.Delta, m.StockAfter, m.Reason, m.Reference,
+	).Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListByProduct mengambil satu halaman riwayat stok produk filter.ProductID, termasuk pergerakan variannya
+// kecuali filter.VariantID diisi. Urutan default (diatur handler) adalah yang terbaru lebih dulu.
+func (repo *StockMovementRepository) ListByProduct(ctx context.Context, filter models.StockMovementFilter) (*models.Page[models.StockMovement], error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var q queryBuilder
+	q.where("product_id = " + q.arg(filter.ProductID))
+	if filter.VariantID != nil {
+		q.where("variant_id = " + q.arg(*filter.VariantID))
+	}
+	if filter.Reason != "" {
+		q.where("reason = " + q.arg(filter.Reason))
+	}
+
+	page := &models.Page[models.StockMovement]{Items: make([]models.StockMovement, 0)}
+	countQuery := "SELECT COUNT(*) FROM stock_movements" + q.whereClause()
+	if err := repo.db.QueryRowContext(ctx, repo.db.Rebind(countQuery), q.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	q.keyset("id", "id", filter.ListOptions)
+	query := "SELECT id, product_id, variant_id, delta, stock_after, reason, COALESCE(reference, ''), created_at" +
+		" FROM stock_movements" + q.whereClause() + q.orderLimit("id", "id", filter.ListOptions)
+	rows, err := repo.db.QueryContext(ctx, repo.db.Rebind(query), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var m models.StockMovement
+		var variantID sql.NullInt64
+		if err := rows.Scan(&m.ID, &m.ProductID, &variantID, &m.Delta, &m.StockAfter, &m.Reason, &m.Reference, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		if variantID.Valid {
+			id := int(variantID.Int64)
+			m.VariantID = &id
+		}
+		page.Items = append(page.Items, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Items) > filter.Limit {
+		page.Items = page.Items[:filter.Limit]
+		page.NextCursor = filter.CursorFor(page.Items[filter.Limit-1])
+	}
+	return page, nil
+}
+
+// Reconcile membandingkan stok setiap produk (termasuk yang sudah dihapus) dan setiap varian dengan jumlah
+// delta di ledger, lalu mengembalikan yang tidak cocok, diurutkan berdasarkan produk lalu varian.
+func (repo *StockMovementRepository) Reconcile(ctx context.Context) ([]models.StockDiscrepancy, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	query := `
+		SELECT p.id, 0, p.stock, COALESCE(SUM(m.delta), 0)
+		FROM product p
+		LEFT JOIN stock_movements m ON m.product_id = p.id AND m.variant_id IS NULL
+		GROUP BY p.id, p.stock
+		HAVING p.stock <> COALESCE(SUM(m.delta), 0)
+		UNION ALL
+		SELECT v.product_id, v.id, v.stock, COALESCE(SUM(m.delta), 0)
+		FROM product_variant v
+		LEFT JOIN stock_movements m ON m.variant_id = v.id
+		GROUP BY v.id, v.product_id, v.stock
+		HAVING v.stock <> COALESCE(SUM(m.delta), 0)
+		ORDER BY 1, 2`
+	rows, err := repo.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	discrepancies := make([]models.StockDiscrepancy, 0)
+	for rows.Next() {
+		var d models.StockDiscrepancy
+		var variantID int
+		if err := rows.Scan(&d.ProductID, &variantID, &d.Stock, &d.LedgerStock); err != nil {
+			return nil, err
+		}
+		if variantID != 0 {
+			d.VariantID = &variantID
+		}
+		discrepancies = append(discrepancies, d)
+	}
+	return discrepancies, rows.Err()
+}
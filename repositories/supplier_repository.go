@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"task-session-1/database"
+	"task-session-1/models"
+)
+
+// SupplierRepository adalah struct yang menyimpan koneksi database untuk operasi supplier.
+type SupplierRepository struct {
+	db *database.DB
+}
+
+// NewSupplierRepository adalah konstruktor untuk membuat instance SupplierRepository.
+func NewSupplierRepository(db *database.DB) *SupplierRepository {
+	return &SupplierRepository{db: db}
+}
+
+// supplierColumns adalah kolom SELECT untuk supplier, sesuai urutan supplierDest.
+const supplierColumns = "id, name, contact_name, phone, email"
+
+// supplierDest mengembalikan tujuan Scan untuk kolom dari supplierColumns.
+func supplierDest(s *models.Supplier) []interface{} {
+	return []interface{}{&s.ID, &s.Name, &s.ContactName, &s.Phone, &s.Email}
+}
+
+// supplierSortColumns memetakan field pengurutan ke kolom tabel supplier.
+var supplierSortColumns = map[string]string{
+	"id":   "id",
+	"name": "name",
+}
+
+// GetAll mengambil satu halaman supplier. Jika filter.Name tidak kosong, hanya supplier yang namanya
+// mengandung nilai tersebut yang diambil.
+func (repo *SupplierRepository) GetAll(ctx context.Context, filter models.SupplierFilter) (*models.Page[models.Supplier], error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var q queryBuilder
+	if filter.Name != "" {
+		q.where("name " + repo.db.Dialect.ILike() + " " + q.arg("%"+filter.Name+"%"))
+	}
+
+	page := &models.Page[models.Supplier]{Items: make([]models.Supplier, 0)}
+	countQuery := "SELECT COUNT(*) FROM supplier" + q.whereClause()
+	if err := repo.db.QueryRowContext(ctx, repo.db.Rebind(countQuery), q.args...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	column := supplierSortColumns[filter.Sort]
+	q.keyset(column, "id", filter.ListOptions)
+	query := "SELECT " + supplierColumns + " FROM supplier" + q.whereClause() + q.orderLimit(column, "id", filter.ListOptions)
+	rows, err := repo.db.QueryContext(ctx, repo.db.Rebind(query), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s models.Supplier
+		if err := rows.Scan(supplierDest(&s)...); err != nil {
+			return nil, err
+		}
+		page.Items = append(page.Items, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Items) > filter.Limit {
+		page.Items = page.Items[:filter.Limit]
+		page.NextCursor = filter.CursorFor(page.Items[filter.Limit-1])
+	}
+	return page, nil
+}
+
+// Create menyisipkan supplier baru dan mengisi supplier.ID.
+func (repo *SupplierRepository) Create(ctx context.Context, supplier *models.Supplier) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO supplier (name, contact_name, phone, email) VALUES ($1, $2, $3, $4) RETURNING id"
+	return repo.db.QueryRowContext(ctx, repo.db.Rebind(query),
+		supplier.Name, supplier.ContactName, supplier.Phone, supplier.Email,
+	).Scan(&supplier.ID)
+}
+
+// GetByID mengambil satu supplier berdasarkan ID. Jika tidak ditemukan, mengembalikan nil tanpa error.
+func (repo *SupplierRepository) GetByID(ctx context.Context, id int) (*models.Supplier, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var s models.Supplier
+	query := "SELECT " + supplierColumns + " FROM supplier WHERE id = $1"
+	err := repo.db.QueryRowContext(ctx, repo.db.Rebind(query), id).Scan(supplierDest(&s)...)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Update memperbarui data supplier. Mengembalikan NotFoundError jika supplier tidak ditemukan.
+func (repo *SupplierRepository) Update(ctx context.Context, supplier *models.Supplier) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE supplier SET name = $1, contact_name = $2, phone = $3, email = $4 WHERE id = $5"
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query),
+		supplier.Name, supplier.ContactName, supplier.Phone, supplier.Email, supplier.ID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return &models.NotFoundError{Resource: "supplier", ID: supplier.ID}
+	}
+	return nil
+}
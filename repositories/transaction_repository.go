@@ -1,22 +1,31 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"task-session-1/database"
 	"task-session-1/models"
 	"time"
 )
 
+// timestampLayout adalah format timestamp CURRENT_TIMESTAMP di SQLite. Batas rentang laporan diformat dengan
+// layout ini agar perbandingan teks di SQLite sama dengan perbandingan waktu di PostgreSQL.
+const timestampLayout = "2006-01-02 15:04:05"
+
 type TransactionRepository struct {
-	db *sql.DB
+	db *database.DB
 }
 
-func NewTransactionRepository(db *sql.DB) *TransactionRepository {
+func NewTransactionRepository(db *database.DB) *TransactionRepository {
 	return &TransactionRepository{db: db}
 }
 
-func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem) (*models.Transaction, error) {
-	tx, err := repo.db.Begin()
+func (repo *TransactionRepository) CreateTransaction(ctx context.Context, items []models.CheckoutItem) (*models.Transaction, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	tx, err := repo.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -24,50 +33,146 @@ func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem
 
 	totalAmount := 0
 	var details []models.TransactionDetail
+	var movements []models.StockMovement
+	var lowStock []models.LowStockEvent
 
-	for _, item := range items {
-		var productPrice, stock int
-		var productName string
+	for i, item := range items {
+		var productID, productPrice, categoryID, reorderLevel, reorderQty int
+		var productName, sku string
 
-		err := tx.QueryRow(
-			"SELECT name, price, stock FROM product WHERE id = $1",
-			item.ProductID,
-		).Scan(&productName, &productPrice, &stock)
+		// Item merujuk produk dengan ID atau dengan barcode hasil scan.
+		column, ref := "id", interface{}(item.ProductID)
+		if item.Barcode != "" {
+			column, ref = "barcode", item.Barcode
+		}
+		err := tx.QueryRowContext(
+			ctx,
+			repo.db.Rebind("SELECT id, name, price, category_id, COALESCE(sku, ''), reorder_level, reorder_qty"+
+				" FROM product WHERE "+column+" = $1 AND deleted_at IS NULL"),
+			ref,
+		).Scan(&productID, &productName, &productPrice, &categoryID, &sku, &reorderLevel, &reorderQty)
 
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("product id %d not found", item.ProductID)
+			return nil, item.ProductNotFound()
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Produk yang punya varian dijual per varian: harga dan stok diambil dari varian.
+		var variant *models.ProductVariant
+		if item.VariantID != 0 {
+			v, err := scanVariant(tx.QueryRowContext(
+				ctx,
+				repo.db.Rebind("SELECT "+variantColumns+" FROM product_variant WHERE id = $1 AND product_id = $2 AND deleted_at IS NULL"),
+				item.VariantID,
+				productID,
+			))
+			if err == sql.ErrNoRows {
+				return nil, &models.NotFoundError{Resource: "variant", ID: item.VariantID}
+			}
+			if err != nil {
+				return nil, err
+			}
+			variant = &v
+			productPrice = v.EffectivePrice(productPrice)
+		} else {
+			var variants int
+			err = tx.QueryRowContext(
+				ctx,
+				repo.db.Rebind("SELECT COUNT(*) FROM product_variant WHERE product_id = $1 AND deleted_at IS NULL"),
+				productID,
+			).Scan(&variants)
+			if err != nil {
+				return nil, err
+			}
+			if variants > 0 {
+				return nil, models.NewValidationError(fmt.Sprintf("items[%d].variant_id", i), "is required for products with variants")
+			}
+		}
+
+		// Stok dibaca ulang dengan lock baris sebelum diperiksa, agar checkout yang berjalan bersamaan menunggu
+		// giliran dan tidak menjual stok yang sama dua kali.
+		table, stockID := "product", productID
+		if variant != nil {
+			table, stockID = "product_variant", variant.ID
+		}
+		stock, err := lockStock(ctx, repo.db, tx, table, stockID)
+		if err == sql.ErrNoRows {
+			if variant != nil {
+				return nil, &models.NotFoundError{Resource: "variant", ID: item.VariantID}
+			}
+			return nil, item.ProductNotFound()
 		}
 		if err != nil {
 			return nil, err
 		}
 
 		if stock < item.Quantity {
-			return nil, fmt.Errorf("insufficient stock for product id %d", item.ProductID)
+			return nil, &models.InsufficientStockError{
+				ProductID: productID,
+				VariantID: item.VariantID,
+				Requested: item.Quantity,
+				Available: stock,
+			}
 		}
 
 		subtotal := productPrice * item.Quantity
 		totalAmount += subtotal
 
-		_, err = tx.Exec(
-			"UPDATE product SET stock = stock - $1 WHERE id = $2",
-			item.Quantity,
-			item.ProductID,
-		)
+		var stockAfter int
+		stockQuery := "UPDATE " + table + " SET stock = stock - $1 WHERE id = $2 RETURNING stock"
+		err = tx.QueryRowContext(ctx, repo.db.Rebind(stockQuery), item.Quantity, stockID).Scan(&stockAfter)
 		if err != nil {
 			return nil, err
 		}
+		movement := models.StockMovement{
+			ProductID:  productID,
+			Delta:      -item.Quantity,
+			StockAfter: stockAfter,
+			Reason:     models.StockSale,
+		}
+		if variant != nil {
+			movement.VariantID = &variant.ID
+		}
+		movements = append(movements, movement)
+
+		// Peringatan hanya dikirim saat stok melewati batas pemesanan ulang, bukan setiap penjualan di bawahnya.
+		if !models.IsLowStock(stockAfter+item.Quantity, reorderLevel) && models.IsLowStock(stockAfter, reorderLevel) {
+			event := models.LowStockEvent{LowStockItem: models.LowStockItem{
+				ProductID:    productID,
+				Name:         productName,
+				SKU:          sku,
+				CategoryID:   categoryID,
+				Stock:        stockAfter,
+				ReorderLevel: reorderLevel,
+				ReorderQty:   reorderQty,
+			}}
+			if variant != nil {
+				event.VariantID = &variant.ID
+				event.VariantName = variant.Label()
+				event.SKU = variant.SKU
+			}
+			lowStock = append(lowStock, event)
+		}
 
-		details = append(details, models.TransactionDetail{
-			ProductID:   item.ProductID,
+		detail := models.TransactionDetail{
+			ProductID:   productID,
 			ProductName: productName,
 			Quantity:    item.Quantity,
 			Subtotal:    subtotal,
-		})
+		}
+		if variant != nil {
+			detail.VariantID = &variant.ID
+			detail.VariantName = variant.Label()
+		}
+		details = append(details, detail)
 	}
 
 	var transactionID int
-	err = tx.QueryRow(
-		"INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id",
+	err = tx.QueryRowContext(
+		ctx,
+		repo.db.Rebind("INSERT INTO transactions (total_amount) VALUES ($1) RETURNING id"),
 		totalAmount,
 	).Scan(&transactionID)
 
@@ -78,10 +183,12 @@ func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem
 	for i := range details {
 		details[i].TransactionID = transactionID
 
-		_, err = tx.Exec(
-			"INSERT INTO transaction_details (transaction_id, product_id, quantity, subtotal) VALUES ($1, $2, $3, $4)",
+		_, err = tx.ExecContext(
+			ctx,
+			repo.db.Rebind("INSERT INTO transaction_details (transaction_id, product_id, variant_id, quantity, subtotal) VALUES ($1, $2, $3, $4, $5)"),
 			details[i].TransactionID,
 			details[i].ProductID,
+			details[i].VariantID,
 			details[i].Quantity,
 			details[i].Subtotal,
 		)
@@ -90,42 +197,65 @@ func (repo *TransactionRepository) CreateTransaction(items []models.CheckoutItem
 		}
 	}
 
+	// Pergerakan stok dicatat setelah ID transaksi diketahui, agar bisa dirujuk dari ledger.
+	for _, m := range movements {
+		m.Reference = models.Reference("transaction", transactionID)
+		if err := recordMovement(ctx, repo.db, tx, m); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
 
+	now := time.Now()
+	for i := range lowStock {
+		lowStock[i].TransactionID = transactionID
+		lowStock[i].OccurredAt = now
+	}
 	return &models.Transaction{
 		ID:          transactionID,
 		TotalAmount: totalAmount,
 		Details:     details,
+		LowStock:    lowStock,
 	}, nil
 }
 
-func (repo *TransactionRepository) GetReport(startDate, endDate time.Time) (*models.ReportResponse, error) {
+// GetReport menghitung laporan transaksi yang dibuat pada rentang [from, to).
+// created_at disimpan dalam UTC, sehingga batas rentang dikirim sebagai timestamp UTC dan index
+// idx_transactions_created_at tetap bisa dipakai.
+func (repo *TransactionRepository) GetReport(ctx context.Context, from, to time.Time) (*models.ReportResponse, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	start := from.UTC().Format(timestampLayout)
+	end := to.UTC().Format(timestampLayout)
+
 	// Total revenue and total transactions
 	var totalRevenue, totalTransaksi int
-	err := repo.db.QueryRow(`
+	err := repo.db.QueryRowContext(ctx, repo.db.Rebind(`
 		SELECT COALESCE(SUM(total_amount), 0), COUNT(*)
 		FROM transactions
-		WHERE DATE(created_at) >= $1 AND DATE(created_at) <= $2
-	`, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).Scan(&totalRevenue, &totalTransaksi)
+		WHERE created_at >= $1 AND created_at < $2
+	`), start, end).Scan(&totalRevenue, &totalTransaksi)
 	if err != nil {
 		return nil, err
 	}
 
-	// Best-selling product
+	// Best-selling product. Produk yang sudah di-soft delete tetap dihitung agar namanya tetap muncul di laporan.
 	var nama string
 	var qtyTerjual int
-	err = repo.db.QueryRow(`
+	err = repo.db.QueryRowContext(ctx, repo.db.Rebind(`
 		SELECT p.name, COALESCE(SUM(td.quantity), 0) as total_qty
 		FROM product p
 		LEFT JOIN transaction_details td ON p.id = td.product_id
 		LEFT JOIN transactions t ON td.transaction_id = t.id
-		WHERE DATE(t.created_at) >= $1 AND DATE(t.created_at) <= $2
+		WHERE t.created_at >= $1 AND t.created_at < $2
 		GROUP BY p.id, p.name
 		ORDER BY total_qty DESC
 		LIMIT 1
-	`, startDate.Format("2006-01-02"), endDate.Format("2006-01-02")).Scan(&nama, &qtyTerjual)
+	`), start, end).Scan(&nama, &qtyTerjual)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -0,0 +1,206 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"task-session-1/database"
+	"task-session-1/models"
+)
+
+// VariantRepository adalah struct yang menyimpan koneksi database untuk operasi varian produk.
+type VariantRepository struct {
+	db *database.DB
+}
+
+// NewVariantRepository adalah konstruktor untuk membuat instance VariantRepository.
+func NewVariantRepository(db *database.DB) *VariantRepository {
+	return &VariantRepository{db: db}
+}
+
+// variantColumns adalah daftar kolom SELECT untuk varian, sesuai urutan di scanVariant.
+const variantColumns = "id, product_id, COALESCE(sku, ''), options, price, stock"
+
+// scanVariant membaca satu baris dengan kolom dari variantColumns ke struct ProductVariant.
+// Kolom options disimpan sebagai teks JSON.
+func scanVariant(row rowScanner) (models.ProductVariant, error) {
+	var v models.ProductVariant
+	var options string
+	var price sql.NullInt64
+	if err := row.Scan(&v.ID, &v.ProductID, &v.SKU, &options, &price, &v.Stock); err != nil {
+		return models.ProductVariant{}, err
+	}
+	if err := json.Unmarshal([]byte(options), &v.Options); err != nil {
+		return models.ProductVariant{}, err
+	}
+	if price.Valid {
+		p := int(price.Int64)
+		v.Price = &p
+	}
+	return v, nil
+}
+
+// encodeOptions mengubah opsi varian menjadi teks JSON. json.Marshal mengurutkan key map,
+// sehingga kombinasi opsi yang sama selalu menghasilkan teks yang sama untuk unique index.
+func encodeOptions(options map[string]string) (string, error) {
+	b, err := json.Marshal(options)
+	return string(b), err
+}
+
+// variantConflict mengubah pelanggaran unique index SKU atau opsi varian menjadi ConflictError.
+func variantConflict(err error) error {
+	if database.IsUniqueViolation(err) {
+		return &models.ConflictError{Message: "variant sku or options are already used by another variant"}
+	}
+	return err
+}
+
+// nullablePrice mengubah harga varian menjadi nilai kolom; nil disimpan sebagai NULL.
+func nullablePrice(price *int) interface{} {
+	if price == nil {
+		return nil
+	}
+	return *price
+}
+
+// ListByProducts mengambil varian aktif dari beberapa produk sekaligus dengan satu query,
+// dikelompokkan per ID produk dan diurutkan berdasarkan ID varian.
+func (repo *VariantRepository) ListByProducts(ctx context.Context, productIDs []int) (map[int][]models.ProductVariant, error) {
+	variants := make(map[int][]models.ProductVariant)
+	if len(productIDs) == 0 {
+		return variants, nil
+	}
+
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	var q queryBuilder
+	placeholders := make([]string, len(productIDs))
+	for i, id := range productIDs {
+		placeholders[i] = q.arg(id)
+	}
+	query := "SELECT " + variantColumns + " FROM product_variant" +
+		" WHERE product_id IN (" + strings.Join(placeholders, ", ") + ") AND deleted_at IS NULL" +
+		" ORDER BY product_id, id"
+
+	rows, err := repo.db.QueryContext(ctx, repo.db.Rebind(query), q.args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		v, err := scanVariant(rows)
+		if err != nil {
+			return nil, err
+		}
+		variants[v.ProductID] = append(variants[v.ProductID], v)
+	}
+	return variants, rows.Err()
+}
+
+// GetByID mengambil satu varian aktif berdasarkan ID.
+// Jika varian tidak ditemukan atau sudah dihapus, mengembalikan nil tanpa error.
+func (repo *VariantRepository) GetByID(ctx context.Context, id int) (*models.ProductVariant, error) {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + variantColumns + " FROM product_variant WHERE id = $1 AND deleted_at IS NULL"
+	v, err := scanVariant(repo.db.QueryRowContext(ctx, repo.db.Rebind(query), id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Create menyisipkan varian baru dan mengisi ID-nya. Stok awal varian dicatat di ledger stock_movements
+// dalam transaksi yang sama.
+// Mengembalikan ConflictError jika SKU atau kombinasi opsi sudah dipakai varian aktif lain.
+func (repo *VariantRepository) Create(ctx context.Context, variant *models.ProductVariant) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	options, err := encodeOptions(variant.Options)
+	if err != nil {
+		return err
+	}
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO product_variant (product_id, sku, options, price, stock)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5) RETURNING id`
+	err = tx.QueryRowContext(ctx, repo.db.Rebind(query),
+		variant.ProductID, variant.SKU, options, nullablePrice(variant.Price), variant.Stock,
+	).Scan(&variant.ID)
+	if database.IsForeignKeyViolation(err) {
+		return &models.NotFoundError{Resource: "product", ID: variant.ProductID}
+	}
+	if err != nil {
+		return variantConflict(err)
+	}
+
+	err = recordMovement(ctx, repo.db, tx, models.StockMovement{
+		ProductID:  variant.ProductID,
+		VariantID:  &variant.ID,
+		Delta:      variant.Stock,
+		StockAfter: variant.Stock,
+		Reason:     models.StockInitial,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Update memperbarui SKU, opsi, dan harga varian aktif berdasarkan ID.
+// Stok tidak ikut diubah (gunakan penyesuaian stok); variant.Stock diisi dengan stok saat ini.
+// Mengembalikan NotFoundError jika varian tidak ditemukan.
+func (repo *VariantRepository) Update(ctx context.Context, variant *models.ProductVariant) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	options, err := encodeOptions(variant.Options)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE product_variant SET sku = NULLIF($1, ''), options = $2, price = $3
+		WHERE id = $4 AND deleted_at IS NULL RETURNING stock`
+	err = repo.db.QueryRowContext(ctx, repo.db.Rebind(query),
+		variant.SKU, options, nullablePrice(variant.Price), variant.ID,
+	).Scan(&variant.Stock)
+	if err == sql.ErrNoRows {
+		return &models.NotFoundError{Resource: "variant", ID: variant.ID}
+	}
+	return variantConflict(err)
+}
+
+// Delete menghapus varian secara soft delete, sehingga transaction_details yang merujuknya tetap valid.
+// Mengembalikan NotFoundError jika varian tidak ditemukan atau sudah dihapus.
+func (repo *VariantRepository) Delete(ctx context.Context, id int) error {
+	ctx, cancel := repo.db.WithTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE product_variant SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL"
+	result, err := repo.db.ExecContext(ctx, repo.db.Rebind(query), id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return &models.NotFoundError{Resource: "variant", ID: id}
+	}
+	return nil
+}
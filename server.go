@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"task-session-1/config"
+)
+
+// newServer membuat http.Server dengan batas waktu dari konfigurasi,
+// sehingga client yang lambat tidak bisa menahan koneksi selamanya.
+func newServer(cfg *config.Config, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              "0.0.0.0:" + cfg.Port,
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+}
+
+// serve menjalankan server sampai menerima SIGINT/SIGTERM atau gagal listen.
+// Saat sinyal diterima, server berhenti menerima koneksi baru lalu menunggu request
+// yang sedang berjalan (misalnya checkout) selesai paling lama shutdownTimeout.
+// Jika batas waktu terlewati, koneksi yang tersisa ditutup paksa.
+func serve(server *http.Server, shutdownTimeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	// Sinyal kedua setelah ini akan langsung menghentikan proses seperti biasa.
+	stop()
+	log.Printf("Shutting down server, waiting up to %s for in-flight requests", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Graceful shutdown timed out, closing remaining connections:", err)
+		return server.Close()
+	}
+
+	log.Println("Server stopped")
+	return nil
+}
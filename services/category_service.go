@@ -1,54 +1,148 @@
 package services
 
 import (
+	"context"
 	"task-session-1/models"
 	"task-session-1/repositories"
 )
 
 // CategoryService adalah struct yang menyimpan dependency untuk operasi kategori.
-// repo adalah pointer ke CategoryRepository yang digunakan untuk mengakses database.
+// repo adalah CategoryStore yang digunakan untuk mengakses penyimpanan data kategori.
 type CategoryService struct {
-	repo *repositories.CategoryRepository
+	repo repositories.CategoryStore
 }
 
 // NewCategoryService adalah konstruktor untuk membuat instance CategoryService.
-// Fungsi ini menerima CategoryRepository sebagai parameter.
+// Fungsi ini menerima implementasi CategoryStore (SQL atau in-memory) sebagai parameter.
 // Mengembalikan pointer ke CategoryService yang siap digunakan.
-func NewCategoryService(repo *repositories.CategoryRepository) *CategoryService {
+func NewCategoryService(repo repositories.CategoryStore) *CategoryService {
 	return &CategoryService{repo: repo}
 }
 
-// GetAll mengambil semua data kategori dari database.
-// Fungsi ini memanggil method GetAll dari CategoryRepository.
-// Mengembalikan slice dari Category dan error jika ada.
-func (s *CategoryService) GetAll() ([]models.Category, error) {
-	return s.repo.GetAll()
+// GetAll mengambil satu halaman kategori sesuai filter, pengurutan, dan paginasi.
+// Filter divalidasi terlebih dahulu, lalu diteruskan ke method GetAll dari CategoryRepository.
+// Mengembalikan Page berisi kategori dan error jika ada.
+func (s *CategoryService) GetAll(ctx context.Context, filter models.CategoryFilter) (*models.Page[models.Category], error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAll(ctx, filter)
 }
 
-// Create membuat kategori baru.
+// Create membuat kategori baru setelah field-nya divalidasi.
 // Fungsi ini memanggil method Create dari CategoryRepository.
-// Mengembalikan error jika penyimpanan gagal.
-func (s *CategoryService) Create(data *models.Category) error {
-	return s.repo.Create(data)
+// Mengembalikan ValidationError jika data tidak valid, atau error jika penyimpanan gagal.
+func (s *CategoryService) Create(ctx context.Context, data *models.Category) error {
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	// product_count hanya bisa dibaca; kategori baru belum punya produk.
+	data.ProductCount = 0
+	return s.repo.Create(ctx, data)
 }
 
 // GetByID mengambil satu kategori berdasarkan ID.
 // Fungsi ini memanggil method GetByID dari CategoryRepository.
-// Mengembalikan pointer ke Category dan error jika ada.
-func (s *CategoryService) GetByID(id int) (*models.Category, error) {
-	return s.repo.GetByID(id)
+// opts.IncludeDeleted menentukan apakah kategori yang sudah dihapus tetap dikembalikan.
+// Mengembalikan NotFoundError jika kategori tidak ditemukan.
+func (s *CategoryService) GetByID(ctx context.Context, id int, opts models.CategoryOptions) (*models.Category, error) {
+	category, err := s.repo.GetByID(ctx, id, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if category == nil {
+		return nil, &models.NotFoundError{Resource: "category", ID: id}
+	}
+
+	return category, nil
+}
+
+// Update memperbarui data kategori setelah field-nya divalidasi.
+// Fungsi ini memanggil method Update dari CategoryRepository, lalu membaca ulang kategori
+// agar product_count pada response sesuai dengan data di database.
+// Mengembalikan ValidationError jika data tidak valid, atau error jika update gagal.
+func (s *CategoryService) Update(ctx context.Context, category *models.Category) error {
+	if err := category.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.Update(ctx, category); err != nil {
+		return err
+	}
+
+	updated, err := s.GetByID(ctx, category.ID, models.CategoryOptions{})
+	if err != nil {
+		return err
+	}
+	*category = *updated
+	return nil
 }
 
-// Update memperbarui data kategori.
-// Fungsi ini memanggil method Update dari CategoryRepository.
-// Mengembalikan error jika update gagal.
-func (s *CategoryService) Update(category *models.Category) error {
-	return s.repo.Update(category)
+// Patch memperbarui sebagian field kategori dan mengembalikan kategori setelah diperbarui.
+// Mengembalikan ValidationError jika field yang diisi tidak valid, atau NotFoundError jika kategori tidak ditemukan.
+func (s *CategoryService) Patch(ctx context.Context, id int, patch models.CategoryPatch) (*models.Category, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Patch(ctx, id, patch); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id, models.CategoryOptions{})
 }
 
-// Delete menghapus kategori berdasarkan ID.
-// Fungsi ini memanggil method Delete dari CategoryRepository.
-// Mengembalikan error jika delete gagal.
-func (s *CategoryService) Delete(id int) error {
-	return s.repo.Delete(id)
+// Delete menghapus kategori berdasarkan ID secara soft delete sesuai policy.
+// Mode kosong dianggap restrict: kategori yang masih punya produk aktif ditolak dengan ConflictError
+// berisi jumlah produk yang menghalangi. Mode reassign memindahkan produk ke kategori tujuan,
+// dan mode cascade ikut menghapus produk aktif; keduanya dijalankan repository dalam satu transaksi.
+// Mengembalikan jumlah produk yang dipindahkan atau ikut dihapus.
+func (s *CategoryService) Delete(ctx context.Context, id int, policy models.CategoryDeletePolicy) (int, error) {
+	if err := policy.Validate(id); err != nil {
+		return 0, err
+	}
+
+	category, err := s.GetByID(ctx, id, models.CategoryOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	switch policy.Mode {
+	case models.CategoryDeleteRestrict:
+		// Pemeriksaan awal agar client langsung tahu jumlah produk yang menghalangi;
+		// repository tetap memeriksa ulang di dalam transaksi.
+		if category.ProductCount > 0 {
+			return 0, &models.ConflictError{
+				Message: "category is still referenced by products",
+				Details: map[string]interface{}{"product_count": category.ProductCount},
+			}
+		}
+	case models.CategoryDeleteReassign:
+		target, err := s.repo.GetByID(ctx, policy.TargetCategoryID, models.CategoryOptions{})
+		if err != nil {
+			return 0, err
+		}
+		if target == nil {
+			return 0, models.NewValidationError("target_category_id", "category not found")
+		}
+	}
+
+	return s.repo.Delete(ctx, id, policy)
+}
+
+// Restore mengembalikan kategori yang sudah dihapus dan mengembalikan kategori setelah dipulihkan.
+// Kategori yang belum dihapus dikembalikan apa adanya, sehingga restore aman dipanggil ulang.
+// Mengembalikan NotFoundError jika kategori tidak ditemukan.
+func (s *CategoryService) Restore(ctx context.Context, id int) (*models.Category, error) {
+	category, err := s.GetByID(ctx, id, models.CategoryOptions{IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+	if category.DeletedAt == nil {
+		return category, nil
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id, models.CategoryOptions{})
 }
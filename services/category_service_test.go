@@ -0,0 +1,225 @@
+package services
+
+import (
+	"strings"
+	"task-session-1/models"
+	"testing"
+)
+
+func TestCategoryCreateValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		category  models.Category
+		wantField string
+	}{
+		{name: "valid", category: models.Category{Name: "Drinks", Description: "Cold and hot drinks"}},
+		{name: "empty name", category: models.Category{Name: "  "}, wantField: "name"},
+		{name: "name too long", category: models.Category{Name: strings.Repeat("a", models.MaxCategoryNameLength+1)}, wantField: "name"},
+		{
+			name:      "description too long",
+			category:  models.Category{Name: "Drinks", Description: strings.Repeat("a", models.MaxCategoryDescriptionLength+1)},
+			wantField: "description",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			err := env.categories.Create(env.ctx, &tt.category)
+			if tt.wantField != "" {
+				wantFieldError(t, err, tt.wantField)
+				return
+			}
+			if err != nil {
+				t.Fatalf("create: %v", err)
+			}
+			if tt.category.ID == 0 {
+				t.Error("created category has no ID")
+			}
+		})
+	}
+}
+
+func TestCategoryCRUD(t *testing.T) {
+	env := newTestEnv(t)
+
+	category := models.Category{Name: "Drinks"}
+	if err := env.categories.Create(env.ctx, &category); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	id := category.ID
+
+	category = models.Category{ID: id, Name: "Beverages", Description: "Cold and hot drinks"}
+	if err := env.categories.Update(env.ctx, &category); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+
+	description := ""
+	patched, err := env.categories.Patch(env.ctx, id, models.CategoryPatch{Description: &description})
+	if err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	if patched.Name != "Beverages" || patched.Description != "" {
+		t.Errorf("patched category = %+v, want name Beverages without description", patched)
+	}
+
+	got, err := env.categories.GetByID(env.ctx, id, models.CategoryOptions{})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Name != "Beverages" || got.ProductCount != 0 {
+		t.Errorf("category = %+v, want name Beverages without products", got)
+	}
+
+	// Kategori yang masih punya produk tidak bisa dihapus dengan mode default (restrict).
+	productID := env.product(t, id, "TEA", 1)
+	_, err = env.categories.Delete(env.ctx, id, models.CategoryDeletePolicy{})
+	if conflict := wantError[models.ConflictError](t, err); conflict.Details["product_count"] != 1 {
+		t.Errorf("conflict details = %v, want product_count 1", conflict.Details)
+	}
+	if err := env.products.Delete(env.ctx, productID); err != nil {
+		t.Fatalf("delete product: %v", err)
+	}
+
+	if _, err := env.categories.Delete(env.ctx, id, models.CategoryDeletePolicy{}); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	_, err = env.categories.GetByID(env.ctx, id, models.CategoryOptions{})
+	wantError[models.NotFoundError](t, err)
+
+	restored, err := env.categories.Restore(env.ctx, id)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Error("restored category is still deleted")
+	}
+}
+
+func TestCategoryDeletePolicy(t *testing.T) {
+	// Kategori Drinks berisi produk aktif A dan B serta produk C yang sudah dihapus. Snacks berisi produk D,
+	// dan Archive adalah kategori yang sudah dihapus. target berisi nama kategori tujuan mode reassign.
+	tests := []struct {
+		name         string
+		mode         models.CategoryDeleteMode
+		target       string
+		targetID     int
+		wantErr      func(t *testing.T, err error)
+		wantAffected int
+		// wantCategory dan wantDeleted berisi kategori dan status hapus produk A, B, C, dan D setelah Delete.
+		wantCategory []string
+		wantDeleted  []bool
+	}{
+		{
+			name: "restrict rejects category with products",
+			wantErr: func(t *testing.T, err error) {
+				if conflict := wantError[models.ConflictError](t, err); conflict.Details["product_count"] != 2 {
+					t.Errorf("conflict details = %v, want product_count 2", conflict.Details)
+				}
+			},
+			wantCategory: []string{"Drinks", "Drinks", "Drinks", "Snacks"},
+			wantDeleted:  []bool{false, false, true, false},
+		},
+		{
+			name:         "reassign moves products to the target",
+			mode:         models.CategoryDeleteReassign,
+			target:       "Snacks",
+			wantAffected: 3,
+			wantCategory: []string{"Snacks", "Snacks", "Snacks", "Snacks"},
+			wantDeleted:  []bool{false, false, true, false},
+		},
+		{
+			name:         "reassign to a missing category",
+			mode:         models.CategoryDeleteReassign,
+			targetID:     99,
+			wantErr:      func(t *testing.T, err error) { wantFieldError(t, err, "target_category_id") },
+			wantCategory: []string{"Drinks", "Drinks", "Drinks", "Snacks"},
+			wantDeleted:  []bool{false, false, true, false},
+		},
+		{
+			name:         "reassign to a deleted category",
+			mode:         models.CategoryDeleteReassign,
+			target:       "Archive",
+			wantErr:      func(t *testing.T, err error) { wantFieldError(t, err, "target_category_id") },
+			wantCategory: []string{"Drinks", "Drinks", "Drinks", "Snacks"},
+			wantDeleted:  []bool{false, false, true, false},
+		},
+		{
+			name:         "reassign to the deleted category itself",
+			mode:         models.CategoryDeleteReassign,
+			target:       "Drinks",
+			wantErr:      func(t *testing.T, err error) { wantFieldError(t, err, "target_category_id") },
+			wantCategory: []string{"Drinks", "Drinks", "Drinks", "Snacks"},
+			wantDeleted:  []bool{false, false, true, false},
+		},
+		{
+			name:         "cascade deletes active products",
+			mode:         models.CategoryDeleteCascade,
+			wantAffected: 2,
+			wantCategory: []string{"Drinks", "Drinks", "Drinks", "Snacks"},
+			wantDeleted:  []bool{true, true, true, false},
+		},
+	}
+
+	for _, backend := range testBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				env := backend.env(t)
+				categories := map[string]int{}
+				names := map[int]string{}
+				for _, name := range []string{"Drinks", "Snacks", "Archive"} {
+					categories[name] = env.category(t, name)
+					names[categories[name]] = name
+				}
+				if _, err := env.categories.Delete(env.ctx, categories["Archive"], models.CategoryDeletePolicy{}); err != nil {
+					t.Fatalf("delete category Archive: %v", err)
+				}
+				productIDs := []int{
+					env.product(t, categories["Drinks"], "A", 1),
+					env.product(t, categories["Drinks"], "B", 1),
+					env.product(t, categories["Drinks"], "C", 1),
+					env.product(t, categories["Snacks"], "D", 1),
+				}
+				if err := env.products.Delete(env.ctx, productIDs[2]); err != nil {
+					t.Fatalf("delete product C: %v", err)
+				}
+
+				policy := models.CategoryDeletePolicy{Mode: tt.mode, TargetCategoryID: tt.targetID}
+				if tt.target != "" {
+					policy.TargetCategoryID = categories[tt.target]
+				}
+				affected, err := env.categories.Delete(env.ctx, categories["Drinks"], policy)
+				if tt.wantErr != nil {
+					tt.wantErr(t, err)
+				} else if err != nil {
+					t.Fatalf("delete: %v", err)
+				}
+				if affected != tt.wantAffected {
+					t.Errorf("affected = %d, want %d", affected, tt.wantAffected)
+				}
+
+				// Kategori hanya terhapus jika policy berhasil; jika gagal, tidak ada produk yang berubah.
+				_, err = env.categories.GetByID(env.ctx, categories["Drinks"], models.CategoryOptions{})
+				if tt.wantErr != nil && err != nil {
+					t.Errorf("category is deleted after a failed delete: %v", err)
+				}
+				if tt.wantErr == nil {
+					wantError[models.NotFoundError](t, err)
+				}
+
+				for i, id := range productIDs {
+					product, err := env.products.GetByID(env.ctx, id, models.ProductOptions{IncludeDeleted: true})
+					if err != nil {
+						t.Fatalf("get product %d: %v", id, err)
+					}
+					if got := names[product.CategoryID]; got != tt.wantCategory[i] {
+						t.Errorf("product %s category = %s, want %s", product.SKU, got, tt.wantCategory[i])
+					}
+					if deleted := product.DeletedAt != nil; deleted != tt.wantDeleted[i] {
+						t.Errorf("product %s deleted = %v, want %v", product.SKU, deleted, tt.wantDeleted[i])
+					}
+				}
+			})
+		}
+	}
+}
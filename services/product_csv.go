@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"task-session-1/models"
+)
+
+// productCSVColumns adalah header file export produk.
+// File hasil export bisa di-import kembali; kolom id diabaikan oleh import karena upsert memakai SKU.
+var productCSVColumns = []string{"id", "sku", "barcode", "name", "price", "stock", "category_id", "category", "reorder_level", "reorder_qty"}
+
+// productImportColumns adalah kolom wajib import produk; kolom category boleh diganti category_id.
+var productImportColumns = [][]string{{"name"}, {"price"}, {"stock"}, {"category", "category_id"}}
+
+// importRecord adalah satu baris data CSV import, dengan key nama kolom dari header.
+type importRecord struct {
+	line   int
+	values map[string]string
+}
+
+// get mengambil nilai kolom yang sudah dibuang spasinya, atau string kosong jika kolom tidak ada.
+func (rec importRecord) get(column string) string {
+	return strings.TrimSpace(rec.values[column])
+}
+
+// Import membaca file CSV produk lalu membuat atau memperbarui produk di dalamnya.
+// Kolom wajib: name, price, stock, dan category (nama atau ID kategori) atau category_id.
+// Kolom sku, barcode, reorder_level, dan reorder_qty opsional; kolom lain diabaikan. Baris dengan SKU milik
+// produk aktif memperbarui produk tersebut (barcode, reorder_level, atau reorder_qty yang kosong mempertahankan
+// nilai lama), baris lain membuat produk baru. Kolom stock hanya menjadi stok awal produk baru; stok produk yang
+// diperbarui tidak berubah.
+// Import bersifat all-or-nothing: jika ada baris yang tidak valid, dikembalikan ImportError berisi
+// kesalahan semua baris dan tidak ada produk yang disimpan. Dengan dryRun, hasil hanya dihitung tanpa disimpan.
+func (s *ProductService) Import(ctx context.Context, r io.Reader, dryRun bool) (*models.ProductImportResult, error) {
+	records, err := readImportCSV(r, productImportColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.importCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ProductImportResult{
+		DryRun: dryRun,
+		Total:  len(records),
+		Rows:   make([]models.ProductImportRow, 0, len(records)),
+	}
+	products := make([]models.Product, 0, len(records))
+	var rowErrors []models.ImportRowError
+
+	// Baris pertama yang memakai SKU atau barcode tertentu, untuk menolak kode ganda di dalam file yang sama.
+	seen := map[string]map[string]int{"sku": {}, "barcode": {}}
+
+	for _, rec := range records {
+		product, fields, err := s.importProduct(ctx, rec, categories)
+		if err != nil {
+			return nil, err
+		}
+
+		for field, value := range map[string]string{"sku": product.SKU, "barcode": product.Barcode} {
+			if value == "" {
+				continue
+			}
+			if line, ok := seen[field][value]; ok {
+				fields[field] = fmt.Sprintf("duplicates line %d", line)
+			} else {
+				seen[field][value] = rec.line
+			}
+		}
+
+		if len(fields) > 0 {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: rec.line, Fields: fields})
+			continue
+		}
+
+		action := models.ImportCreate
+		if product.ID != 0 {
+			action = models.ImportUpdate
+			result.Updated++
+		} else {
+			result.Created++
+		}
+		products = append(products, product)
+		result.Rows = append(result.Rows, models.ProductImportRow{
+			Line:      rec.line,
+			Action:    action,
+			ProductID: product.ID,
+			SKU:       product.SKU,
+			Name:      product.Name,
+		})
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, &models.ImportError{Rows: rowErrors}
+	}
+	if dryRun || len(products) == 0 {
+		return result, nil
+	}
+
+	if err := s.productRepo.Import(ctx, products); err != nil {
+		return nil, err
+	}
+	for i := range products {
+		result.Rows[i].ProductID = products[i].ID
+	}
+	return result, nil
+}
+
+// importProduct mengubah satu baris CSV menjadi produk dan memvalidasinya dengan aturan yang sama seperti Create.
+// Jika SKU sudah dipakai produk aktif, ID produk tersebut diisi sehingga produk diperbarui.
+// Kesalahan pada baris dikembalikan sebagai pesan per kolom; error hanya dikembalikan jika lookup gagal.
+func (s *ProductService) importProduct(ctx context.Context, rec importRecord, categories *categoryIndex) (models.Product, map[string]string, error) {
+	fields := make(map[string]string)
+	reorderLevel := importOptionalInt(rec, "reorder_level", fields)
+	reorderQty := importOptionalInt(rec, "reorder_qty", fields)
+	product := models.Product{
+		Name:       rec.get("name"),
+		Price:      importInt(rec, "price", fields),
+		Stock:      importInt(rec, "stock", fields),
+		CategoryID: categories.resolve(rec, fields),
+		SKU:        rec.get("sku"),
+		Barcode:    rec.get("barcode"),
+	}
+	if reorderLevel != nil {
+		product.ReorderLevel = *reorderLevel
+	}
+	if reorderQty != nil {
+		product.ReorderQty = *reorderQty
+	}
+
+	// Kesalahan kolom dari parsing di atas lebih spesifik, sehingga tidak ditimpa hasil Validate.
+	// Kategori yang gagal dicocokkan sudah dilaporkan, baik dari kolom category maupun category_id.
+	_, categoryErr := fields["category"]
+	var validationErr *models.ValidationError
+	if err := product.Validate(); errors.As(err, &validationErr) {
+		for field, message := range validationErr.Fields {
+			if _, exists := fields[field]; !exists && !(field == "category_id" && categoryErr) {
+				fields[field] = message
+			}
+		}
+	}
+	if len(fields) > 0 {
+		return product, fields, nil
+	}
+
+	if product.SKU != "" {
+		existing, err := s.productRepo.GetBySKU(ctx, product.SKU, models.ProductOptions{})
+		if err != nil {
+			return product, nil, err
+		}
+		if existing != nil {
+			product.ID = existing.ID
+			if product.Barcode == "" {
+				product.Barcode = existing.Barcode
+			}
+			if reorderLevel == nil {
+				product.ReorderLevel = existing.ReorderLevel
+			}
+			if reorderQty == nil {
+				product.ReorderQty = existing.ReorderQty
+			}
+		}
+	}
+
+	if product.Barcode != "" {
+		other, err := s.productRepo.GetByBarcode(ctx, product.Barcode, models.ProductOptions{})
+		if err != nil {
+			return product, nil, err
+		}
+		if other != nil && other.ID != product.ID {
+			fields["barcode"] = fmt.Sprintf("is already used by product %d", other.ID)
+		}
+	}
+	return product, fields, nil
+}
+
+// importInt membaca kolom bilangan bulat dan mencatat pesan kesalahan di fields jika tidak valid.
+func importInt(rec importRecord, column string, fields map[string]string) int {
+	raw := rec.get(column)
+	if raw == "" {
+		fields[column] = "is required"
+		return 0
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		fields[column] = "must be an integer"
+		return 0
+	}
+	return n
+}
+
+// importOptionalInt membaca kolom bilangan bulat opsional, atau nil jika kolom tidak ada atau kosong.
+// Pesan kesalahan dicatat di fields jika nilainya bukan bilangan bulat.
+func importOptionalInt(rec importRecord, column string, fields map[string]string) *int {
+	if rec.get(column) == "" {
+		return nil
+	}
+	n := importInt(rec, column, fields)
+	return &n
+}
+
+// readImportCSV membaca header dan semua baris data dari file CSV import.
+// Setiap elemen required berisi nama kolom yang saling menggantikan; jika tidak ada satu pun, kolom pertama
+// dilaporkan hilang. Nama kolom di header tidak membedakan huruf besar/kecil, dan BOM dari Excel dibuang.
+// Mengembalikan ValidationError jika file bukan CSV yang valid, kolom wajib tidak ada,
+// atau jumlah baris melebihi MaxImportRows.
+func readImportCSV(r io.Reader, required [][]string) ([]importRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, models.NewValidationError("file", "is empty")
+	}
+	if err != nil {
+		return nil, csvError(err)
+	}
+
+	columns := make(map[string]bool, len(header))
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		if columns[column] {
+			return nil, models.NewValidationError("file", "duplicate column "+strconv.Quote(column))
+		}
+		columns[column] = true
+		header[i] = column
+	}
+
+	var missing []string
+	for _, alternatives := range required {
+		if !slices.ContainsFunc(alternatives, func(column string) bool { return columns[column] }) {
+			missing = append(missing, alternatives[0])
+		}
+	}
+	if len(missing) > 0 {
+		return nil, models.NewValidationError("file", "missing columns: "+strings.Join(missing, ", "))
+	}
+
+	var records []importRecord
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, csvError(err)
+		}
+		if len(records) == models.MaxImportRows {
+			return nil, models.NewValidationError("file", fmt.Sprintf("must not contain more than %d rows", models.MaxImportRows))
+		}
+
+		line, _ := reader.FieldPos(0)
+		rec := importRecord{line: line, values: make(map[string]string, len(header))}
+		for i, column := range header {
+			rec.values[column] = values[i]
+		}
+		records = append(records, rec)
+	}
+}
+
+// csvError mengubah kesalahan format CSV menjadi ValidationError.
+// Error lain, misalnya body yang melebihi batas ukuran, dikembalikan apa adanya.
+func csvError(err error) error {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return models.NewValidationError("file", "invalid CSV: "+parseErr.Error())
+	}
+	return err
+}
+
+// categoryIndex berisi kategori aktif untuk mencocokkan kolom category pada import.
+// Nama kategori tidak unik, sehingga nama dipetakan ke semua ID kategori dengan nama tersebut.
+type categoryIndex struct {
+	ids   map[int]bool
+	names map[string][]int
+}
+
+// importCategories mengambil semua kategori aktif, halaman demi halaman.
+func (s *ProductService) importCategories(ctx context.Context) (*categoryIndex, error) {
+	categories := &categoryIndex{ids: make(map[int]bool), names: make(map[string][]int)}
+	filter := models.CategoryFilter{ListOptions: models.ListOptions{Limit: models.MaxPageLimit, Sort: "id"}}
+	for {
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+		page, err := s.categoryRepo.GetAll(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range page.Items {
+			name := strings.ToLower(strings.TrimSpace(c.Name))
+			categories.ids[c.ID] = true
+			categories.names[name] = append(categories.names[name], c.ID)
+		}
+		if page.NextCursor == "" {
+			return categories, nil
+		}
+		if filter.Cursor, err = models.DecodeCursor(page.NextCursor); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// resolve mengambil ID kategori dari kolom category_id, atau dari kolom category yang berisi
+// ID atau nama kategori (tanpa membedakan huruf besar/kecil). Nilai berupa angka selalu dianggap ID.
+// Kesalahan dicatat di fields dengan key nama kolom yang dipakai.
+func (c *categoryIndex) resolve(rec importRecord, fields map[string]string) int {
+	column := "category_id"
+	value := rec.get(column)
+	if value == "" {
+		column = "category"
+		value = rec.get(column)
+	}
+	if value == "" {
+		fields["category"] = "is required"
+		return 0
+	}
+
+	if id, err := strconv.Atoi(value); err == nil {
+		if !c.ids[id] {
+			fields[column] = "category not found"
+		}
+		return id
+	}
+	if column == "category_id" {
+		fields[column] = "must be an integer"
+		return 0
+	}
+
+	ids := c.names[strings.ToLower(value)]
+	switch len(ids) {
+	case 0:
+		fields[column] = "category not found"
+		return 0
+	case 1:
+		return ids[0]
+	default:
+		fields[column] = fmt.Sprintf("matches %d categories, use the category id instead", len(ids))
+		return 0
+	}
+}
+
+// Export menulis produk yang cocok dengan filter ke w sebagai CSV dengan kolom productCSVColumns.
+// Produk diambil halaman demi halaman dengan cursor, dan setiap halaman langsung ditulis ke w,
+// sehingga katalog besar tidak perlu dimuat ke memori sekaligus. Paginasi dari filter diabaikan.
+// Error sebelum header ditulis (misalnya filter tidak valid) bisa dikirim sebagai response error biasa.
+func (s *ProductService) Export(ctx context.Context, w io.Writer, filter models.ProductFilter) error {
+	filter.Limit, filter.Offset, filter.Cursor = models.MaxPageLimit, 0, nil
+	filter.IncludeCategory, filter.IncludeVariants = true, false
+
+	out := csv.NewWriter(w)
+	for first := true; ; first = false {
+		page, err := s.GetAll(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if first {
+			out.Write(productCSVColumns)
+		}
+		for _, p := range page.Items {
+			var category string
+			if p.Category != nil {
+				category = p.Category.Name
+			}
+			out.Write([]string{
+				strconv.Itoa(p.ID), p.SKU, p.Barcode, p.Name,
+				strconv.Itoa(p.Price), strconv.Itoa(p.Stock),
+				strconv.Itoa(p.CategoryID), category,
+				strconv.Itoa(p.ReorderLevel), strconv.Itoa(p.ReorderQty),
+			})
+		}
+
+		out.Flush()
+		if err := out.Error(); err != nil {
+			return err
+		}
+		if page.NextCursor == "" {
+			return nil
+		}
+		if filter.Cursor, err = models.DecodeCursor(page.NextCursor); err != nil {
+			return err
+		}
+	}
+}
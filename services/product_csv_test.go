@@ -0,0 +1,61 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"task-session-1/models"
+	"testing"
+)
+
+func TestProductImportStock(t *testing.T) {
+	tests := []struct {
+		name        string
+		csv         string
+		wantStock   map[string]int
+		wantReasons map[string][]models.StockReason
+	}{
+		{
+			name:        "update keeps existing stock",
+			csv:         "name,price,stock,category_id,sku\nTea,5000,999,1,TEA\n",
+			wantStock:   map[string]int{"TEA": 10},
+			wantReasons: map[string][]models.StockReason{"TEA": {models.StockInitial}},
+		},
+		{
+			name:        "new product gets initial stock",
+			csv:         "name,price,stock,category_id,sku\nCoffee,7000,7,1,COFFEE\n",
+			wantStock:   map[string]int{"TEA": 10, "COFFEE": 7},
+			wantReasons: map[string][]models.StockReason{"COFFEE": {models.StockImport}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			categoryID := env.category(t, "Drinks")
+			env.product(t, categoryID, "TEA", 10)
+
+			if _, err := env.products.Import(env.ctx, strings.NewReader(tt.csv), false); err != nil {
+				t.Fatalf("import: %v", err)
+			}
+
+			for sku, want := range tt.wantStock {
+				product, err := env.products.productRepo.GetBySKU(env.ctx, sku, models.ProductOptions{})
+				if err != nil || product == nil {
+					t.Fatalf("get product %s: %v", sku, err)
+				}
+				if product.Stock != want {
+					t.Errorf("%s stock = %d, want %d", sku, product.Stock, want)
+				}
+				if reasons, ok := tt.wantReasons[sku]; ok {
+					var got []models.StockReason
+					for _, m := range env.history(t, product.ID) {
+						got = append(got, m.Reason)
+					}
+					if !slices.Equal(got, reasons) {
+						t.Errorf("%s ledger reasons = %v, want %v", sku, got, reasons)
+					}
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package services
 
 import (
-	"errors"
+	"context"
 	"task-session-1/models"
 	"task-session-1/repositories"
 )
@@ -9,107 +9,323 @@ import (
 // ProductService adalah struct yang menyimpan dependency untuk operasi produk.
 // productRepo digunakan untuk mengakses data produk di database.
 // categoryRepo digunakan untuk validasi kategori saat membuat produk.
+// variantRepo digunakan untuk mengisi varian produk jika diminta dengan include=variants.
 type ProductService struct {
-	productRepo  *repositories.ProductRepository
-	categoryRepo *repositories.CategoryRepository
+	productRepo  repositories.ProductStore
+	categoryRepo repositories.CategoryStore
+	variantRepo  repositories.VariantStore
 }
 
 // NewProductService adalah konstruktor untuk membuat instance ProductService.
-// Fungsi ini menerima ProductRepository dan CategoryRepository sebagai parameter.
+// Fungsi ini menerima implementasi ProductStore, CategoryStore, dan VariantStore sebagai parameter.
 // Mengembalikan pointer ke ProductService yang siap digunakan.
 func NewProductService(
-	productRepo *repositories.ProductRepository,
-	categoryRepo *repositories.CategoryRepository,
+	productRepo repositories.ProductStore,
+	categoryRepo repositories.CategoryStore,
+	variantRepo repositories.VariantStore,
 ) *ProductService {
 	return &ProductService{
 		productRepo:  productRepo,
 		categoryRepo: categoryRepo,
+		variantRepo:  variantRepo,
 	}
 }
 
-// GetAll mengambil semua data produk dari database.
-// Fungsi ini memanggil method GetAll dari ProductRepository.
-// Mengembalikan slice dari Product dan error jika ada.
-func (s *ProductService) GetAll(name string) ([]models.Product, error) {
-	return s.productRepo.GetAll(name)
+// GetAll mengambil satu halaman produk sesuai filter, pengurutan, dan paginasi.
+// Filter divalidasi terlebih dahulu, lalu diteruskan ke method GetAll dari ProductRepository.
+// Mengembalikan Page berisi produk dan error jika ada.
+func (s *ProductService) GetAll(ctx context.Context, filter models.ProductFilter) (*models.Page[models.Product], error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	page, err := s.productRepo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if filter.IncludeVariants {
+		if err := s.attachVariants(ctx, page.Items); err != nil {
+			return nil, err
+		}
+	}
+	return page, nil
+}
+
+// Suggest mengambil produk aktif yang paling relevan dengan query untuk autocomplete kotak pencarian kasir.
+// Memakai pencarian yang sama dengan GetAll (parameter q), tetapi hanya mengembalikan field yang perlu ditampilkan.
+func (s *ProductService) Suggest(ctx context.Context, filter models.SuggestFilter) ([]models.ProductSuggestion, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	products := models.ProductFilter{
+		ListOptions:    models.ListOptions{Limit: filter.Limit},
+		ProductOptions: models.ProductOptions{IncludeCategory: true},
+		Query:          filter.Query,
+	}
+	products.SetSort("-relevance")
+	page, err := s.GetAll(ctx, products)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions := make([]models.ProductSuggestion, len(page.Items))
+	for i, p := range page.Items {
+		suggestions[i] = models.ProductSuggestion{ID: p.ID, Name: p.Name, SKU: p.SKU, Price: p.Price, Stock: p.Stock}
+		if p.Category != nil {
+			suggestions[i].Category = p.Category.Name
+		}
+	}
+	return suggestions, nil
+}
+
+// withVariants mengisi varian satu produk jika opts.IncludeVariants aktif.
+func (s *ProductService) withVariants(ctx context.Context, product *models.Product, opts models.ProductOptions) (*models.Product, error) {
+	if !opts.IncludeVariants {
+		return product, nil
+	}
+	products := []models.Product{*product}
+	if err := s.attachVariants(ctx, products); err != nil {
+		return nil, err
+	}
+	return &products[0], nil
+}
+
+// attachVariants mengisi field Variants setiap produk dengan varian aktifnya dalam satu query.
+// Produk tanpa varian tetap tanpa field variants.
+func (s *ProductService) attachVariants(ctx context.Context, products []models.Product) error {
+	ids := make([]int, len(products))
+	for i, p := range products {
+		ids[i] = p.ID
+	}
+
+	variants, err := s.variantRepo.ListByProducts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		products[i].Variants = variants[products[i].ID]
+	}
+	return nil
+}
+
+// GetByCategory mengambil satu halaman produk milik kategori tertentu.
+// Filter lain (nama, harga, stok, paginasi) sama seperti GetAll, tetapi category_id selalu diambil dari categoryID.
+// Mengembalikan NotFoundError jika kategori tidak ditemukan.
+func (s *ProductService) GetByCategory(ctx context.Context, categoryID int, filter models.ProductFilter) (*models.Page[models.Product], error) {
+	category, err := s.categoryRepo.GetByID(ctx, categoryID, models.CategoryOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, &models.NotFoundError{Resource: "category", ID: categoryID}
+	}
+
+	filter.CategoryID = categoryID
+	return s.GetAll(ctx, filter)
 }
 
 // Create membuat produk baru setelah melakukan validasi.
-// Pertama, memeriksa apakah CategoryID tidak kosong (tidak 0).
-// Kemudian, memeriksa apakah kategori dengan ID tersebut ada di database.
+// Pertama, memvalidasi field produk (nama, harga, stok, dan CategoryID) dengan Product.Validate.
+// Kemudian, memeriksa apakah kategori dengan ID tersebut ada di database dan SKU/barcode belum dipakai.
 // Jika validasi lolos, maka produk disimpan ke database.
 // Mengembalikan error jika validasi gagal atau penyimpanan gagal.
-func (s *ProductService) Create(data *models.Product) error {
-	// Validasi: CategoryID tidak boleh kosong.
-	if data.CategoryID == 0 {
-		return errors.New("category cannot empty!")
+func (s *ProductService) Create(ctx context.Context, data *models.Product) error {
+	// Validasi field produk; semua pelanggaran dikembalikan sekaligus.
+	if err := data.Validate(); err != nil {
+		return err
 	}
 
+	// Varian dikelola lewat endpoint varian, bukan bersama produk.
+	data.Variants = nil
+
 	// Mengambil data kategori berdasarkan ID untuk memastikan kategori ada.
-	category, err := s.categoryRepo.GetByID(data.CategoryID)
+	category, err := s.categoryRepo.GetByID(ctx, data.CategoryID, models.CategoryOptions{})
 	if err != nil {
 		return err
 	}
 
-	// Jika kategori tidak ditemukan (nil), kembalikan error.
+	// Jika kategori tidak ditemukan (nil), kembalikan error validasi untuk field category_id.
 	if category == nil {
-		return errors.New("Category not found!")
+		return models.NewValidationError("category_id", "category not found")
+	}
+
+	// SKU dan barcode harus unik di antara produk aktif.
+	if err := s.checkIdentifiers(ctx, 0, data.SKU, data.Barcode); err != nil {
+		return err
 	}
 
 	// Jika semua validasi lolos, simpan produk ke database.
-	return s.productRepo.Create(data)
+	return s.productRepo.Create(ctx, data)
 }
 
-func (s *ProductService) GetByID(id int) (*models.Product, error) {
+// GetByID mengambil satu produk berdasarkan ID.
+// opts menentukan data tambahan yang ikut diambil, misalnya kategori produk.
+// Mengembalikan NotFoundError jika produk tidak ditemukan.
+func (s *ProductService) GetByID(ctx context.Context, id int, opts models.ProductOptions) (*models.Product, error) {
 	if id <= 0 {
-		return nil, errors.New("invalid product id")
+		return nil, &models.NotFoundError{Resource: "product", ID: id}
 	}
 
-	product, err := s.productRepo.GetByID(id)
+	product, err := s.productRepo.GetByID(ctx, id, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	if product == nil {
-		return nil, errors.New("product not found")
+		return nil, &models.NotFoundError{Resource: "product", ID: id}
 	}
 
-	return product, nil
+	return s.withVariants(ctx, product, opts)
 }
 
-func (s *ProductService) Update(product *models.Product) error {
-	if product.CategoryID == 0 {
-		return errors.New("Category cannot empty!")
+func (s *ProductService) Update(ctx context.Context, product *models.Product) error {
+	// Stok hanya bisa diubah lewat penyesuaian stok (handler menolak stock di body);
+	// repository mengisinya kembali dengan stok saat ini.
+	product.Stock = 0
+	if err := product.Validate(); err != nil {
+		return err
 	}
+	product.Variants = nil
 
 	// Mengambil data kategori berdasarkan ID untuk memastikan kategori ada.
-	category, err := s.categoryRepo.GetByID(product.CategoryID)
+	category, err := s.categoryRepo.GetByID(ctx, product.CategoryID, models.CategoryOptions{})
 	if err != nil {
 		return err
 	}
 
-	// Jika kategori tidak ditemukan (nil), kembalikan error.
+	// Jika kategori tidak ditemukan (nil), kembalikan error validasi untuk field category_id.
 	if category == nil {
-		return errors.New("Category not found!")
+		return models.NewValidationError("category_id", "category not found")
 	}
 
-	return s.productRepo.Update(product)
+	if err := s.checkIdentifiers(ctx, product.ID, product.SKU, product.Barcode); err != nil {
+		return err
+	}
+
+	return s.productRepo.Update(ctx, product)
 }
 
-// Delete menghapus kategori berdasarkan ID.
-// Fungsi ini memanggil method Delete dari CategoryRepository.
-// Mengembalikan error jika delete gagal.
-func (s *ProductService) Delete(id int) error {
-	// Ambil product
-	product, err := s.productRepo.GetByID(id)
-	if err != nil {
-		return err
+// Patch memperbarui sebagian field produk dan mengembalikan produk setelah diperbarui.
+// Field yang diisi divalidasi dengan aturan yang sama seperti Create, termasuk keberadaan kategori.
+// Mengembalikan NotFoundError jika produk tidak ditemukan.
+func (s *ProductService) Patch(ctx context.Context, id int, patch models.ProductPatch) (*models.Product, error) {
+	if err := patch.Validate(); err != nil {
+		return nil, err
+	}
+
+	if patch.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, *patch.CategoryID, models.CategoryOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if category == nil {
+			return nil, models.NewValidationError("category_id", "category not found")
+		}
+	}
+
+	var sku, barcode string
+	if patch.SKU != nil {
+		sku = *patch.SKU
+	}
+	if patch.Barcode != nil {
+		barcode = *patch.Barcode
+	}
+	if err := s.checkIdentifiers(ctx, id, sku, barcode); err != nil {
+		return nil, err
 	}
 
+	if err := s.productRepo.Patch(ctx, id, patch); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id, models.ProductOptions{})
+}
+
+// GetByBarcode mengambil produk aktif berdasarkan barcode hasil scan kasir.
+// Mengembalikan ValidationError jika barcode bukan EAN-13 atau UPC-A yang valid,
+// atau NotFoundError jika tidak ada produk aktif dengan barcode tersebut.
+func (s *ProductService) GetByBarcode(ctx context.Context, barcode string, opts models.ProductOptions) (*models.Product, error) {
+	if !models.ValidBarcode(barcode) {
+		return nil, models.NewValidationError("barcode", "must be a valid EAN-13 or UPC-A barcode")
+	}
+
+	product, err := s.productRepo.GetByBarcode(ctx, barcode, opts)
+	if err != nil {
+		return nil, err
+	}
 	if product == nil {
-		return errors.New("product not found")
+		return nil, &models.NotFoundError{Resource: "product with barcode", ID: barcode}
 	}
+	return s.withVariants(ctx, product, opts)
+}
 
-	// Hapus product
-	return s.productRepo.Delete(id)
+// checkIdentifiers memastikan SKU dan barcode (jika diisi) belum dipakai produk aktif selain produk id.
+// id bernilai 0 untuk produk baru. Mengembalikan ConflictError berisi field dan ID produk yang memakainya.
+func (s *ProductService) checkIdentifiers(ctx context.Context, id int, sku, barcode string) error {
+	lookups := []struct {
+		field string
+		value string
+		get   func(context.Context, string, models.ProductOptions) (*models.Product, error)
+	}{
+		{"sku", sku, s.productRepo.GetBySKU},
+		{"barcode", barcode, s.productRepo.GetByBarcode},
+	}
+
+	for _, lookup := range lookups {
+		if lookup.value == "" {
+			continue
+		}
+		other, err := lookup.get(ctx, lookup.value, models.ProductOptions{})
+		if err != nil {
+			return err
+		}
+		if other != nil && other.ID != id {
+			return &models.ConflictError{
+				Message: lookup.field + " is already used by another product",
+				Details: map[string]interface{}{"field": lookup.field, "product_id": other.ID},
+			}
+		}
+	}
+	return nil
+}
+
+// Delete menghapus produk berdasarkan ID secara soft delete.
+// Produk tetap tersimpan agar riwayat transaksi dan laporan masih bisa menampilkan namanya.
+// Mengembalikan NotFoundError jika produk tidak ditemukan atau sudah dihapus.
+func (s *ProductService) Delete(ctx context.Context, id int) error {
+	return s.productRepo.Delete(ctx, id)
+}
+
+// Restore mengembalikan produk yang sudah dihapus dan mengembalikan produk setelah dipulihkan.
+// Produk yang belum dihapus dikembalikan apa adanya, sehingga restore aman dipanggil ulang.
+// Mengembalikan NotFoundError jika produk tidak ditemukan, atau ConflictError jika kategorinya sudah dihapus
+// atau SKU/barcode-nya sudah dipakai produk lain.
+func (s *ProductService) Restore(ctx context.Context, id int) (*models.Product, error) {
+	product, err := s.GetByID(ctx, id, models.ProductOptions{IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+	if product.DeletedAt == nil {
+		return product, nil
+	}
+
+	category, err := s.categoryRepo.GetByID(ctx, product.CategoryID, models.CategoryOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		return nil, &models.ConflictError{
+			Message: "product category is deleted; restore the category first",
+			Details: map[string]interface{}{"category_id": product.CategoryID},
+		}
+	}
+
+	if err := s.checkIdentifiers(ctx, id, product.SKU, product.Barcode); err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id, models.ProductOptions{})
 }
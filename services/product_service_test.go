@@ -0,0 +1,98 @@
+package services
+
+import (
+	"task-session-1/models"
+	"testing"
+)
+
+func TestProductCreateValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		product   models.Product
+		wantField string
+		wantErr   bool
+	}{
+		{name: "valid", product: models.Product{Name: "Coffee", Price: 7000, Stock: 3, CategoryID: 1, SKU: "COFFEE"}},
+		{name: "missing name", product: models.Product{Price: 7000, CategoryID: 1}, wantField: "name"},
+		{name: "negative price", product: models.Product{Name: "Coffee", Price: -1, CategoryID: 1}, wantField: "price"},
+		{name: "negative stock", product: models.Product{Name: "Coffee", Stock: -1, CategoryID: 1}, wantField: "stock"},
+		{name: "unknown category", product: models.Product{Name: "Coffee", CategoryID: 99}, wantField: "category_id"},
+		{name: "duplicate sku", product: models.Product{Name: "Coffee", CategoryID: 1, SKU: "TEA"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			env.product(t, env.category(t, "Drinks"), "TEA", 1)
+
+			err := env.products.Create(env.ctx, &tt.product)
+			switch {
+			case tt.wantField != "":
+				wantFieldError(t, err, tt.wantField)
+			case tt.wantErr:
+				if conflict := wantError[models.ConflictError](t, err); conflict.Details["field"] != "sku" {
+					t.Errorf("conflict details = %v, want field sku", conflict.Details)
+				}
+			case err != nil:
+				t.Fatalf("create: %v", err)
+			case tt.product.ID == 0:
+				t.Error("created product has no ID")
+			}
+		})
+	}
+}
+
+func TestProductCRUD(t *testing.T) {
+	env := newTestEnv(t)
+	drinks := env.category(t, "Drinks")
+	snacks := env.category(t, "Snacks")
+
+	product := models.Product{Name: "Tea", Price: 5000, Stock: 10, CategoryID: drinks, SKU: "TEA"}
+	if err := env.products.Create(env.ctx, &product); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	id := product.ID
+
+	// PUT menimpa semua field kecuali stok.
+	product = models.Product{ID: id, Name: "Green Tea", Price: 6000, CategoryID: snacks, SKU: "GREEN-TEA"}
+	if err := env.products.Update(env.ctx, &product); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if product.Stock != 10 {
+		t.Errorf("stock after update = %d, want 10", product.Stock)
+	}
+
+	price, sku := 6500, ""
+	patched, err := env.products.Patch(env.ctx, id, models.ProductPatch{Price: &price, SKU: &sku})
+	if err != nil {
+		t.Fatalf("patch: %v", err)
+	}
+	want := models.Product{ID: id, Name: "Green Tea", Price: 6500, Stock: 10, CategoryID: snacks}
+	if patched.ID != want.ID || patched.Name != want.Name || patched.Price != want.Price ||
+		patched.Stock != want.Stock || patched.CategoryID != want.CategoryID || patched.SKU != want.SKU {
+		t.Errorf("patched product = %+v, want %+v", patched, want)
+	}
+
+	got, err := env.products.GetByID(env.ctx, id, models.ProductOptions{IncludeCategory: true})
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if got.Category == nil || got.Category.Name != "Snacks" {
+		t.Errorf("category = %+v, want Snacks", got.Category)
+	}
+
+	if err := env.products.Delete(env.ctx, id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	_, err = env.products.GetByID(env.ctx, id, models.ProductOptions{})
+	wantError[models.NotFoundError](t, err)
+	wantError[models.NotFoundError](t, env.products.Delete(env.ctx, id))
+
+	restored, err := env.products.Restore(env.ctx, id)
+	if err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if restored.DeletedAt != nil || restored.Stock != 10 {
+		t.Errorf("restored product = %+v, want active with stock 10", restored)
+	}
+}
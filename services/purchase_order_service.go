@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"task-session-1/models"
+	"task-session-1/repositories"
+)
+
+// PurchaseOrderService adalah struct yang menyimpan dependency untuk purchase order.
+// supplierRepo, productRepo, dan variantRepo digunakan untuk memastikan supplier, produk, dan varian
+// di purchase order baru ada.
+type PurchaseOrderService struct {
+	orderRepo    repositories.PurchaseOrderStore
+	supplierRepo repositories.SupplierStore
+	productRepo  repositories.ProductStore
+	variantRepo  repositories.VariantStore
+}
+
+// NewPurchaseOrderService adalah konstruktor untuk membuat instance PurchaseOrderService.
+func NewPurchaseOrderService(orderRepo repositories.PurchaseOrderStore, supplierRepo repositories.SupplierStore,
+	productRepo repositories.ProductStore, variantRepo repositories.VariantStore) *PurchaseOrderService {
+	return &PurchaseOrderService{orderRepo: orderRepo, supplierRepo: supplierRepo, productRepo: productRepo, variantRepo: variantRepo}
+}
+
+// GetAll mengambil satu halaman purchase order sesuai filter status dan supplier, pengurutan, dan paginasi.
+func (s *PurchaseOrderService) GetAll(ctx context.Context, filter models.PurchaseOrderFilter) (*models.Page[models.PurchaseOrder], error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.GetAll(ctx, filter)
+}
+
+// GetByID mengambil satu purchase order beserta barisnya. Mengembalikan NotFoundError jika tidak ditemukan.
+func (s *PurchaseOrderService) GetByID(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	po, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if po == nil {
+		return nil, &models.NotFoundError{Resource: "purchase order", ID: id}
+	}
+	return po, nil
+}
+
+// Create membuat purchase order baru berstatus ordered, lalu mengisi po dengan data yang disimpan.
+// Seperti checkout, baris untuk produk yang punya varian wajib menyertakan variant_id.
+// Mengembalikan ValidationError jika supplier, produk, atau varian tidak ditemukan.
+func (s *PurchaseOrderService) Create(ctx context.Context, po *models.PurchaseOrder) error {
+	if err := po.Validate(); err != nil {
+		return err
+	}
+
+	supplier, err := s.supplierRepo.GetByID(ctx, po.SupplierID)
+	if err != nil {
+		return err
+	}
+	if supplier == nil {
+		return models.NewValidationError("supplier_id", "supplier not found")
+	}
+	if err := s.checkItems(ctx, po.Items); err != nil {
+		return err
+	}
+
+	if err := s.orderRepo.Create(ctx, po); err != nil {
+		return err
+	}
+
+	created, err := s.GetByID(ctx, po.ID)
+	if err != nil {
+		return err
+	}
+	*po = *created
+	return nil
+}
+
+// checkItems memastikan setiap baris merujuk produk aktif, dan varian aktif milik produk tersebut jika produknya
+// punya varian. Semua pelanggaran dikembalikan sekaligus dengan key seperti "items[0].variant_id".
+func (s *PurchaseOrderService) checkItems(ctx context.Context, items []models.PurchaseOrderItem) error {
+	productIDs := make([]int, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	variants, err := s.variantRepo.ListByProducts(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	fields := make(map[string]string)
+	for i, item := range items {
+		prefix := fmt.Sprintf("items[%d].", i)
+		product, err := s.productRepo.GetByID(ctx, item.ProductID, models.ProductOptions{})
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			fields[prefix+"product_id"] = "product not found"
+			continue
+		}
+
+		productVariants := variants[item.ProductID]
+		switch {
+		case item.VariantID == nil && len(productVariants) > 0:
+			fields[prefix+"variant_id"] = "is required for products with variants"
+		case item.VariantID != nil && !slices.ContainsFunc(productVariants, func(v models.ProductVariant) bool { return v.ID == *item.VariantID }):
+			fields[prefix+"variant_id"] = "variant not found"
+		}
+	}
+	if len(fields) > 0 {
+		return &models.ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// Receive menerima barang untuk purchase order id dan menambah stok produk atau variannya, lalu mengembalikan
+// purchase order setelah diperbarui. Receipt tanpa items menerima semua sisa barang (penerimaan penuh).
+// Mengembalikan NotFoundError jika purchase order tidak ada, ConflictError jika sudah received atau cancelled,
+// dan ValidationError jika quantity melebihi sisa barang.
+func (s *PurchaseOrderService) Receive(ctx context.Context, id int, receipt models.PurchaseOrderReceipt) (*models.PurchaseOrder, error) {
+	if err := receipt.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.orderRepo.Receive(ctx, id, receipt); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// Cancel membatalkan purchase order id yang masih open dan mengembalikan purchase order setelah diperbarui.
+// Barang yang sudah diterima tidak dikembalikan.
+func (s *PurchaseOrderService) Cancel(ctx context.Context, id int) (*models.PurchaseOrder, error) {
+	if err := s.orderRepo.Cancel(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
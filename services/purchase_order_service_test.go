@@ -0,0 +1,160 @@
+package services
+
+import (
+	"slices"
+	"task-session-1/models"
+	"testing"
+)
+
+func TestPurchaseOrderReceive(t *testing.T) {
+	// receiptStep adalah satu penerimaan barang: quantities memetakan indeks baris ke jumlah yang diterima,
+	// dan nil berarti penerimaan penuh.
+	type receiptStep struct {
+		quantities map[int]int
+		wantErr    func(t *testing.T, err error)
+		wantStatus models.PurchaseOrderStatus
+	}
+
+	// Purchase order memesan 10 unit produk A (stok awal 10) dan 4 unit produk B (stok awal 5).
+	tests := []struct {
+		name          string
+		deleteProduct bool
+		steps         []receiptStep
+		wantReceived  []int
+		wantStock     []int
+	}{
+		{
+			name: "partial then full receipt",
+			steps: []receiptStep{
+				{quantities: map[int]int{0: 4}, wantStatus: models.PurchaseOrderPartiallyReceived},
+				{quantities: map[int]int{1: 4}, wantStatus: models.PurchaseOrderPartiallyReceived},
+				{wantStatus: models.PurchaseOrderReceived},
+			},
+			wantReceived: []int{10, 4},
+			wantStock:    []int{20, 9},
+		},
+		{
+			name: "over-receipt is rejected",
+			steps: []receiptStep{
+				{quantities: map[int]int{0: 4}, wantStatus: models.PurchaseOrderPartiallyReceived},
+				{
+					quantities: map[int]int{0: 7, 1: 1},
+					wantErr:    func(t *testing.T, err error) { wantFieldError(t, err, "items[0].quantity") },
+					wantStatus: models.PurchaseOrderPartiallyReceived,
+				},
+			},
+			wantReceived: []int{4, 0},
+			wantStock:    []int{14, 5},
+		},
+		{
+			name: "received order cannot receive again",
+			steps: []receiptStep{
+				{wantStatus: models.PurchaseOrderReceived},
+				{
+					quantities: map[int]int{0: 1},
+					wantErr:    func(t *testing.T, err error) { wantError[models.ConflictError](t, err) },
+					wantStatus: models.PurchaseOrderReceived,
+				},
+			},
+			wantReceived: []int{10, 4},
+			wantStock:    []int{20, 9},
+		},
+		{
+			name:          "deleted product rolls back the whole receipt",
+			deleteProduct: true,
+			steps: []receiptStep{
+				{
+					wantErr: func(t *testing.T, err error) {
+						conflict := wantError[models.ConflictError](t, err)
+						if conflict.Message != "product 2 has been deleted" {
+							t.Errorf("message = %q, want product 2 has been deleted", conflict.Message)
+						}
+					},
+					wantStatus: models.PurchaseOrderOrdered,
+				},
+			},
+			wantReceived: []int{0, 0},
+			wantStock:    []int{10, 5},
+		},
+	}
+
+	for _, backend := range testBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				env := backend.env(t)
+				categoryID := env.category(t, "Drinks")
+				productIDs := []int{env.product(t, categoryID, "A", 10), env.product(t, categoryID, "B", 5)}
+				supplier := models.Supplier{Name: "Supplier"}
+				if err := env.suppliers.Create(env.ctx, &supplier); err != nil {
+					t.Fatalf("create supplier: %v", err)
+				}
+				po := models.PurchaseOrder{SupplierID: supplier.ID, Items: []models.PurchaseOrderItem{
+					{ProductID: productIDs[0], Quantity: 10, UnitCost: 3000},
+					{ProductID: productIDs[1], Quantity: 4, UnitCost: 2000},
+				}}
+				if err := env.orders.Create(env.ctx, &po); err != nil {
+					t.Fatalf("create purchase order: %v", err)
+				}
+				if tt.deleteProduct {
+					if err := env.products.Delete(env.ctx, productIDs[1]); err != nil {
+						t.Fatalf("delete product: %v", err)
+					}
+				}
+
+				for i, step := range tt.steps {
+					var receipt models.PurchaseOrderReceipt
+					for index, quantity := range step.quantities {
+						receipt.Items = append(receipt.Items, models.PurchaseOrderReceiptItem{ItemID: po.Items[index].ID, Quantity: quantity})
+					}
+					slices.SortFunc(receipt.Items, func(a, b models.PurchaseOrderReceiptItem) int { return a.ItemID - b.ItemID })
+
+					_, err := env.orders.Receive(env.ctx, po.ID, receipt)
+					if step.wantErr != nil {
+						step.wantErr(t, err)
+					} else if err != nil {
+						t.Fatalf("receipt %d: %v", i, err)
+					}
+
+					got, err := env.orders.GetByID(env.ctx, po.ID)
+					if err != nil {
+						t.Fatalf("get purchase order: %v", err)
+					}
+					if got.Status != step.wantStatus {
+						t.Errorf("status after receipt %d = %s, want %s", i, got.Status, step.wantStatus)
+					}
+					po = *got
+				}
+
+				for i, item := range po.Items {
+					if item.ReceivedQuantity != tt.wantReceived[i] {
+						t.Errorf("item %d received %d, want %d", i, item.ReceivedQuantity, tt.wantReceived[i])
+					}
+				}
+				if want := tt.wantReceived[0]*3000 + tt.wantReceived[1]*2000; po.ReceivedCost != want {
+					t.Errorf("received cost = %d, want %d", po.ReceivedCost, want)
+				}
+
+				for i, id := range productIDs {
+					if got := env.stockOf(t, id); got != tt.wantStock[i] {
+						t.Errorf("product %d stock = %d, want %d", id, got, tt.wantStock[i])
+					}
+
+					// Setiap penerimaan tercatat di ledger dengan referensi purchase order-nya.
+					var received int
+					for _, m := range env.history(t, id) {
+						if m.Reason != models.StockPurchaseReceipt {
+							continue
+						}
+						if m.Reference != models.Reference("purchase_order", po.ID) {
+							t.Errorf("receipt movement reference = %q, want purchase_order:%d", m.Reference, po.ID)
+						}
+						received += m.Delta
+					}
+					if received != tt.wantReceived[i] {
+						t.Errorf("product %d received %d in the ledger, want %d", id, received, tt.wantReceived[i])
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"task-session-1/database"
+	"task-session-1/models"
+	"task-session-1/notify"
+	"task-session-1/repositories"
+	"task-session-1/repositories/memory"
+	"testing"
+)
+
+// testEnv berisi service yang memakai satu storage, sehingga test berjalan tanpa server.
+type testEnv struct {
+	ctx          context.Context
+	categories   *CategoryService
+	products     *ProductService
+	variants     *VariantService
+	stock        *StockService
+	transactions *TransactionService
+	suppliers    *SupplierService
+	orders       *PurchaseOrderService
+	counts       *StockCountService
+}
+
+// testStores adalah repository yang dipakai testEnv, dari storage in-memory atau database.
+type testStores struct {
+	categories   repositories.CategoryStore
+	products     repositories.ProductStore
+	variants     repositories.VariantStore
+	transactions repositories.TransactionStore
+	movements    repositories.StockMovementStore
+	suppliers    repositories.SupplierStore
+	orders       repositories.PurchaseOrderStore
+	stockCounts  repositories.StockCountStore
+}
+
+func (s testStores) env() *testEnv {
+	return &testEnv{
+		ctx:          context.Background(),
+		categories:   NewCategoryService(s.categories),
+		products:     NewProductService(s.products, s.categories, s.variants),
+		variants:     NewVariantService(s.variants, s.products),
+		stock:        NewStockService(s.movements, s.products, s.variants),
+		transactions: NewTransactionService(s.transactions, notify.Nop{}),
+		suppliers:    NewSupplierService(s.suppliers),
+		orders:       NewPurchaseOrderService(s.orders, s.suppliers, s.products, s.variants),
+		counts:       NewStockCountService(s.stockCounts, s.categories),
+	}
+}
+
+// newTestEnv membuat testEnv dengan storage in-memory yang kosong.
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	store := memory.NewStore()
+	return testStores{
+		categories:   memory.NewCategoryRepository(store),
+		products:     memory.NewProductRepository(store),
+		variants:     memory.NewVariantRepository(store),
+		transactions: memory.NewTransactionRepository(store),
+		movements:    memory.NewStockMovementRepository(store),
+		suppliers:    memory.NewSupplierRepository(store),
+		orders:       memory.NewPurchaseOrderRepository(store),
+		stockCounts:  memory.NewStockCountRepository(store),
+	}.env()
+}
+
+// newSQLiteTestEnv membuat testEnv dengan database SQLite baru di direktori sementara yang sudah dimigrasi,
+// untuk menguji repository SQL dengan skenario yang sama seperti storage in-memory.
+func newSQLiteTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	// Data test tidak perlu tahan crash, sehingga sinkronisasi ke disk dimatikan agar migrasi cepat.
+	path := filepath.Join(t.TempDir(), "pos.db") + "?_pragma=synchronous(off)"
+	db, err := database.InitDB(database.Config{Driver: string(database.SQLite), ConnString: path})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := database.Migrate(context.Background(), db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return testStores{
+		categories:   repositories.NewCategoryRepository(db),
+		products:     repositories.NewProductRepository(db),
+		variants:     repositories.NewVariantRepository(db),
+		transactions: repositories.NewTransactionRepository(db),
+		movements:    repositories.NewStockMovementRepository(db),
+		suppliers:    repositories.NewSupplierRepository(db),
+		orders:       repositories.NewPurchaseOrderRepository(db),
+		stockCounts:  repositories.NewStockCountRepository(db),
+	}.env()
+}
+
+// testBackends adalah storage yang dipakai test yang harus berperilaku sama di memory dan database.
+var testBackends = []struct {
+	name string
+	env  func(t *testing.T) *testEnv
+}{
+	{"memory", newTestEnv},
+	{"sqlite", newSQLiteTestEnv},
+}
+
+// category membuat kategori baru dan mengembalikan ID-nya.
+func (env *testEnv) category(t *testing.T, name string) int {
+	t.Helper()
+	category := models.Category{Name: name}
+	if err := env.categories.Create(env.ctx, &category); err != nil {
+		t.Fatalf("create category %q: %v", name, err)
+	}
+	return category.ID
+}
+
+// product membuat produk baru dengan stok awal stock dan mengembalikan ID-nya.
+func (env *testEnv) product(t *testing.T, categoryID int, sku string, stock int) int {
+	t.Helper()
+	product := models.Product{Name: "Product " + sku, Price: 1000, Stock: stock, CategoryID: categoryID, SKU: sku}
+	if err := env.products.Create(env.ctx, &product); err != nil {
+		t.Fatalf("create product %q: %v", sku, err)
+	}
+	return product.ID
+}
+
+// reorderAt mengatur batas pemesanan ulang produk id menjadi level.
+func (env *testEnv) reorderAt(t *testing.T, id, level int) {
+	t.Helper()
+	if _, err := env.products.Patch(env.ctx, id, models.ProductPatch{ReorderLevel: &level}); err != nil {
+		t.Fatalf("set reorder level of product %d: %v", id, err)
+	}
+}
+
+// variant membuat varian produk productID dengan opsi size dan stok awal stock, lalu mengembalikan ID-nya.
+func (env *testEnv) variant(t *testing.T, productID int, size string, stock int) int {
+	t.Helper()
+	variant := models.ProductVariant{Options: map[string]string{"size": size}, Stock: stock}
+	if err := env.variants.Create(env.ctx, productID, &variant); err != nil {
+		t.Fatalf("create variant %q of product %d: %v", size, productID, err)
+	}
+	return variant.ID
+}
+
+// variantStockOf mengembalikan stok varian aktif variantID milik produk productID saat ini.
+func (env *testEnv) variantStockOf(t *testing.T, productID, variantID int) int {
+	t.Helper()
+	variants, err := env.variants.List(env.ctx, productID)
+	if err != nil {
+		t.Fatalf("list variants of product %d: %v", productID, err)
+	}
+	for _, v := range variants {
+		if v.ID == variantID {
+			return v.Stock
+		}
+	}
+	t.Fatalf("variant %d of product %d not found", variantID, productID)
+	return 0
+}
+
+// stockOf mengembalikan stok produk id saat ini, termasuk produk yang sudah dihapus.
+func (env *testEnv) stockOf(t *testing.T, id int) int {
+	t.Helper()
+	product, err := env.products.GetByID(env.ctx, id, models.ProductOptions{IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("get product %d: %v", id, err)
+	}
+	return product.Stock
+}
+
+// history mengembalikan semua pergerakan stok produk id, yang terlama lebih dulu.
+func (env *testEnv) history(t *testing.T, id int) []models.StockMovement {
+	t.Helper()
+	filter := models.StockMovementFilter{ListOptions: models.ListOptions{Limit: models.MaxPageLimit, Sort: "id"}, ProductID: id}
+	page, err := env.stock.History(env.ctx, filter)
+	if err != nil {
+		t.Fatalf("stock history of product %d: %v", id, err)
+	}
+	return page.Items
+}
+
+// wantError memastikan err bertipe *T dan mengembalikannya, misalnya wantError[models.ConflictError](t, err).
+func wantError[T any, PT interface {
+	*T
+	error
+}](t *testing.T, err error) PT {
+	t.Helper()
+	var target PT
+	if !errors.As(err, &target) {
+		t.Fatalf("error = %v, want %T", err, target)
+	}
+	return target
+}
+
+// wantFieldError memastikan err adalah ValidationError yang berisi pesan untuk field.
+func wantFieldError(t *testing.T, err error, field string) {
+	t.Helper()
+	validationErr := wantError[models.ValidationError](t, err)
+	if _, ok := validationErr.Fields[field]; !ok {
+		t.Fatalf("validation fields = %v, want an error for %q", validationErr.Fields, field)
+	}
+}
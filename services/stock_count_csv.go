@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"task-session-1/models"
+)
+
+// stockCountImportColumns adalah kolom wajib file hasil hitung stock opname; kolom sku boleh diganti product_id.
+var stockCountImportColumns = [][]string{{"counted_quantity"}, {"sku", "product_id"}}
+
+// ImportCounts membaca file CSV hasil hitung fisik lalu menyimpannya ke sesi stock opname id, seperti RecordCounts.
+// Setiap baris memilih baris sesi dengan kolom sku, atau product_id (dan variant_id untuk varian), dan berisi
+// counted_quantity; kolom lain, misalnya nama produk di lembar hitung, diabaikan.
+// Import bersifat all-or-nothing: jika ada baris yang tidak valid, dikembalikan ImportError berisi kesalahan
+// semua baris dan tidak ada hasil hitung yang disimpan.
+func (s *StockCountService) ImportCounts(ctx context.Context, id int, r io.Reader) (*models.StockCount, error) {
+	records, err := readImportCSV(r, stockCountImportColumns)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, models.NewValidationError("file", "must contain at least one row")
+	}
+
+	count, err := s.openCount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int, len(records))
+	seen := make(map[int]int, len(records))
+	var rowErrors []models.ImportRowError
+	for _, rec := range records {
+		entry, fields := importCountEntry(rec)
+		if len(fields) == 0 {
+			index := count.FindItem(entry)
+			line, duplicate := seen[index]
+			switch {
+			case index < 0:
+				fields[entryField(entry)] = "is not part of this stock count"
+			case duplicate:
+				fields[entryField(entry)] = fmt.Sprintf("duplicates line %d", line)
+			default:
+				seen[index] = rec.line
+				counts[count.Items[index].ID] = *entry.CountedQuantity
+			}
+		}
+		if len(fields) > 0 {
+			rowErrors = append(rowErrors, models.ImportRowError{Line: rec.line, Fields: fields})
+		}
+	}
+	if len(rowErrors) > 0 {
+		return nil, &models.ImportError{Rows: rowErrors}
+	}
+
+	return s.recordCounts(ctx, id, counts)
+}
+
+// importCountEntry mengubah satu baris CSV menjadi hasil hitung dan memvalidasinya dengan aturan yang sama seperti
+// RecordCounts. Kesalahan dikembalikan sebagai pesan per kolom.
+func importCountEntry(rec importRecord) (models.StockCountEntry, map[string]string) {
+	fields := make(map[string]string)
+	entry := models.StockCountEntry{
+		SKU:       rec.get("sku"),
+		VariantID: importOptionalInt(rec, "variant_id", fields),
+	}
+	if productID := importOptionalInt(rec, "product_id", fields); productID != nil {
+		entry.ProductID = *productID
+	}
+	if counted := importInt(rec, "counted_quantity", fields); fields["counted_quantity"] == "" {
+		entry.CountedQuantity = &counted
+	}
+
+	// Kesalahan kolom dari parsing di atas lebih spesifik, sehingga tidak ditimpa hasil Validate.
+	var validationErr *models.ValidationError
+	if err := entry.Validate(); errors.As(err, &validationErr) {
+		for field, message := range validationErr.Fields {
+			if _, exists := fields[field]; !exists {
+				fields[field] = message
+			}
+		}
+	}
+	return entry, fields
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"task-session-1/models"
+	"testing"
+)
+
+func TestStockCountImportCounts(t *testing.T) {
+	// Sesi berisi produk 1 (SKU A) dan produk 2 (SKU B). wantCounted berisi hasil hitung per SKU setelah import;
+	// SKU yang tidak ada berarti belum dihitung.
+	tests := []struct {
+		name        string
+		csv         string
+		wantCounted map[string]int
+		wantRows    []models.ImportRowError
+		wantField   string
+	}{
+		{
+			name:        "match by sku",
+			csv:         "sku,counted_quantity\nA,8\nB,0\n",
+			wantCounted: map[string]int{"A": 8, "B": 0},
+		},
+		{
+			name:        "match by product_id",
+			csv:         "product_id,counted_quantity\n2,3\n",
+			wantCounted: map[string]int{"B": 3},
+		},
+		{
+			name:        "sku or product_id per row and other columns ignored",
+			csv:         "Product_ID,SKU,Name,Counted_Quantity\n,A,Tea,8\n2,,Coffee,3\n",
+			wantCounted: map[string]int{"A": 8, "B": 3},
+		},
+		{
+			name: "duplicate rows are rejected",
+			csv:  "product_id,sku,counted_quantity\n,A,8\n,B,3\n1,,9\n,A,7\n",
+			wantRows: []models.ImportRowError{
+				{Line: 4, Fields: map[string]string{"product_id": "duplicates line 2"}},
+				{Line: 5, Fields: map[string]string{"sku": "duplicates line 2"}},
+			},
+		},
+		{
+			name: "unknown and invalid rows are rejected together",
+			csv:  "sku,product_id,counted_quantity\nZZZ,,1\nA,,-1\n,,2\n",
+			wantRows: []models.ImportRowError{
+				{Line: 2, Fields: map[string]string{"sku": "is not part of this stock count"}},
+				{Line: 3, Fields: map[string]string{"counted_quantity": fmt.Sprintf("must be between 0 and %d", models.MaxStockAdjustment)}},
+				{Line: 4, Fields: map[string]string{"product_id": "is required when sku is empty"}},
+			},
+		},
+		{
+			name:      "missing column",
+			csv:       "sku\nA\n",
+			wantField: "file",
+		},
+		{
+			name:      "no rows",
+			csv:       "sku,counted_quantity\n",
+			wantField: "file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t)
+			categoryID := env.category(t, "Drinks")
+			env.product(t, categoryID, "A", 10)
+			env.product(t, categoryID, "B", 5)
+			var count models.StockCount
+			if err := env.counts.Create(env.ctx, &count); err != nil {
+				t.Fatalf("create stock count: %v", err)
+			}
+
+			_, err := env.counts.ImportCounts(env.ctx, count.ID, strings.NewReader(tt.csv))
+			switch {
+			case tt.wantRows != nil:
+				importErr := wantError[models.ImportError](t, err)
+				if !slices.EqualFunc(importErr.Rows, tt.wantRows, func(a, b models.ImportRowError) bool {
+					return a.Line == b.Line && maps.Equal(a.Fields, b.Fields)
+				}) {
+					t.Errorf("rows = %+v, want %+v", importErr.Rows, tt.wantRows)
+				}
+			case tt.wantField != "":
+				wantFieldError(t, err, tt.wantField)
+			case err != nil:
+				t.Fatalf("import counts: %v", err)
+			}
+
+			// Import yang gagal tidak menyimpan hasil hitung apa pun.
+			got, err := env.counts.GetByID(env.ctx, count.ID)
+			if err != nil {
+				t.Fatalf("get stock count: %v", err)
+			}
+			counted := make(map[string]int)
+			for _, item := range got.Items {
+				if item.CountedQuantity != nil {
+					counted[item.SKU] = *item.CountedQuantity
+				}
+			}
+			want := tt.wantCounted
+			if want == nil {
+				want = map[string]int{}
+			}
+			if !maps.Equal(counted, want) {
+				t.Errorf("counted = %v, want %v", counted, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"task-session-1/models"
+	"task-session-1/repositories"
+)
+
+// StockCountService adalah struct yang menyimpan dependency untuk sesi stock opname.
+// categoryRepo digunakan untuk memastikan kategori sesi baru ada.
+type StockCountService struct {
+	countRepo    repositories.StockCountStore
+	categoryRepo repositories.CategoryStore
+}
+
+// NewStockCountService adalah konstruktor untuk membuat instance StockCountService.
+func NewStockCountService(countRepo repositories.StockCountStore, categoryRepo repositories.CategoryStore) *StockCountService {
+	return &StockCountService{countRepo: countRepo, categoryRepo: categoryRepo}
+}
+
+// GetAll mengambil satu halaman sesi stock opname sesuai filter status, pengurutan, dan paginasi.
+func (s *StockCountService) GetAll(ctx context.Context, filter models.StockCountFilter) (*models.Page[models.StockCount], error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.countRepo.GetAll(ctx, filter)
+}
+
+// GetByID mengambil satu sesi stock opname beserta barisnya. Mengembalikan NotFoundError jika tidak ditemukan.
+func (s *StockCountService) GetByID(ctx context.Context, id int) (*models.StockCount, error) {
+	count, err := s.countRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if count == nil {
+		return nil, &models.NotFoundError{Resource: "stock count", ID: id}
+	}
+	return count, nil
+}
+
+// Create membuka sesi stock opname baru yang menyalin stok saat ini, lalu mengisi count dengan sesi yang disimpan
+// beserta barisnya. Mengembalikan ValidationError jika kategori tidak ditemukan dan ConflictError jika masih ada
+// sesi lain yang open.
+func (s *StockCountService) Create(ctx context.Context, count *models.StockCount) error {
+	if err := count.Validate(); err != nil {
+		return err
+	}
+
+	if count.CategoryID != nil {
+		category, err := s.categoryRepo.GetByID(ctx, *count.CategoryID, models.CategoryOptions{})
+		if err != nil {
+			return err
+		}
+		if category == nil {
+			return models.NewValidationError("category_id", "category not found")
+		}
+	}
+
+	if err := s.countRepo.Create(ctx, count); err != nil {
+		return err
+	}
+
+	created, err := s.GetByID(ctx, count.ID)
+	if err != nil {
+		return err
+	}
+	*count = *created
+	return nil
+}
+
+// RecordCounts menyimpan hasil hitung fisik ke sesi stock opname id dan mengembalikan sesi setelah diperbarui.
+// Setiap hasil hitung harus merujuk baris sesi (produk atau varian yang disalin saat sesi dibuka), dan satu baris
+// tidak boleh dihitung dua kali dalam request yang sama. Semua pelanggaran dikembalikan sekaligus dengan key seperti
+// "items[0].product_id". Mengembalikan ConflictError jika sesi sudah posted atau cancelled.
+func (s *StockCountService) RecordCounts(ctx context.Context, id int, entries models.StockCountEntries) (*models.StockCount, error) {
+	if err := entries.Validate(); err != nil {
+		return nil, err
+	}
+
+	count, err := s.openCount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int, len(entries.Items))
+	seen := make(map[int]int, len(entries.Items))
+	fields := make(map[string]string)
+	for i, entry := range entries.Items {
+		field := fmt.Sprintf("items[%d].%s", i, entryField(entry))
+		index := count.FindItem(entry)
+		if index < 0 {
+			fields[field] = "is not part of this stock count"
+			continue
+		}
+		if first, ok := seen[index]; ok {
+			fields[field] = fmt.Sprintf("counts the same item as items[%d]", first)
+			continue
+		}
+		seen[index] = i
+		counts[count.Items[index].ID] = *entry.CountedQuantity
+	}
+	if len(fields) > 0 {
+		return nil, &models.ValidationError{Fields: fields}
+	}
+
+	return s.recordCounts(ctx, id, counts)
+}
+
+// openCount mengambil sesi stock opname id beserta barisnya dan memastikan sesinya masih open, sehingga hasil hitung
+// untuk sesi yang sudah ditutup ditolak sebelum dicocokkan. Repository memeriksa status sesi sekali lagi saat
+// hasil hitung disimpan.
+func (s *StockCountService) openCount(ctx context.Context, id int) (*models.StockCount, error) {
+	count, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if count.Status != models.StockCountOpen {
+		return nil, models.NewStockCountClosedError(id, count.Status)
+	}
+	return count, nil
+}
+
+// recordCounts menyimpan hasil hitung yang sudah dicocokkan dengan baris sesi, lalu mengambil sesi terbaru.
+func (s *StockCountService) recordCounts(ctx context.Context, id int, counts map[int]int) (*models.StockCount, error) {
+	if err := s.countRepo.RecordCounts(ctx, id, counts); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// entryField mengembalikan nama field yang dipakai entry untuk memilih baris sesi, untuk key pesan kesalahan.
+func entryField(entry models.StockCountEntry) string {
+	if entry.SKU != "" {
+		return "sku"
+	}
+	return "product_id"
+}
+
+// Variance membuat laporan selisih sesi stock opname id. Mengembalikan NotFoundError jika sesi tidak ditemukan.
+func (s *StockCountService) Variance(ctx context.Context, id int) (*models.StockCountVarianceReport, error) {
+	count, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	report := count.VarianceReport()
+	return &report, nil
+}
+
+// Post menerapkan selisih hasil hitung sesi stock opname id ke stok dan mengembalikan sesi setelah diposting.
+// Baris yang belum dihitung tidak mengubah stok.
+func (s *StockCountService) Post(ctx context.Context, id int) (*models.StockCount, error) {
+	if err := s.countRepo.Post(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// Cancel membatalkan sesi stock opname id tanpa mengubah stok dan mengembalikan sesi setelah dibatalkan.
+func (s *StockCountService) Cancel(ctx context.Context, id int) (*models.StockCount, error) {
+	if err := s.countRepo.Cancel(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
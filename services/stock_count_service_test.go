@@ -0,0 +1,153 @@
+package services
+
+import (
+	"task-session-1/models"
+	"testing"
+)
+
+func TestStockCountPost(t *testing.T) {
+	// Sesi dibuka saat produk A berstok 10 dan produk B berstok 5. sales adalah penjualan setelah sesi dibuka
+	// dan counts adalah hasil hitung, keduanya dengan key indeks produk.
+	tests := []struct {
+		name        string
+		sales       map[int]int
+		counts      map[int]int
+		postTwice   bool
+		wantErr     func(t *testing.T, err error)
+		wantStatus  models.StockCountStatus
+		wantStock   []int
+		wantOpnames []int
+	}{
+		{
+			name:        "variance is applied to current stock",
+			counts:      map[int]int{0: 12, 1: 5},
+			wantStatus:  models.StockCountPosted,
+			wantStock:   []int{12, 5},
+			wantOpnames: []int{2, 0},
+		},
+		{
+			name:        "sale after the snapshot is still accounted for",
+			sales:       map[int]int{0: 3},
+			counts:      map[int]int{0: 8},
+			wantStatus:  models.StockCountPosted,
+			wantStock:   []int{5, 5},
+			wantOpnames: []int{-2, 0},
+		},
+		{
+			name:        "uncounted row leaves stock unchanged",
+			sales:       map[int]int{1: 2},
+			counts:      map[int]int{0: 9},
+			wantStatus:  models.StockCountPosted,
+			wantStock:   []int{9, 3},
+			wantOpnames: []int{-1, 0},
+		},
+		{
+			name:      "posting twice returns conflict",
+			counts:    map[int]int{0: 12},
+			postTwice: true,
+			wantErr: func(t *testing.T, err error) {
+				if conflict := wantError[models.ConflictError](t, err); conflict.Details["status"] != models.StockCountPosted {
+					t.Errorf("conflict details = %v, want status posted", conflict.Details)
+				}
+			},
+			wantStatus:  models.StockCountPosted,
+			wantStock:   []int{12, 5},
+			wantOpnames: []int{2, 0},
+		},
+		{
+			name:   "variance that makes stock negative is rejected",
+			sales:  map[int]int{0: 8},
+			counts: map[int]int{0: 5, 1: 6},
+			wantErr: func(t *testing.T, err error) {
+				stockErr := wantError[models.InsufficientStockError](t, err)
+				if stockErr.Requested != 5 || stockErr.Available != 2 {
+					t.Errorf("error = %+v, want requested 5 and available 2", *stockErr)
+				}
+			},
+			wantStatus:  models.StockCountOpen,
+			wantStock:   []int{2, 5},
+			wantOpnames: []int{0, 0},
+		},
+	}
+
+	for _, backend := range testBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				env := backend.env(t)
+				categoryID := env.category(t, "Drinks")
+				productIDs := []int{env.product(t, categoryID, "A", 10), env.product(t, categoryID, "B", 5)}
+
+				var count models.StockCount
+				if err := env.counts.Create(env.ctx, &count); err != nil {
+					t.Fatalf("create stock count: %v", err)
+				}
+				for index, quantity := range tt.sales {
+					items := []models.CheckoutItem{{ProductID: productIDs[index], Quantity: quantity}}
+					if _, err := env.transactions.Checkout(env.ctx, items, false); err != nil {
+						t.Fatalf("checkout: %v", err)
+					}
+				}
+				var entries models.StockCountEntries
+				for index, counted := range tt.counts {
+					entries.Items = append(entries.Items, models.StockCountEntry{ProductID: productIDs[index], CountedQuantity: &counted})
+				}
+				if _, err := env.counts.RecordCounts(env.ctx, count.ID, entries); err != nil {
+					t.Fatalf("record counts: %v", err)
+				}
+
+				_, err := env.counts.Post(env.ctx, count.ID)
+				if tt.postTwice {
+					if err != nil {
+						t.Fatalf("first post: %v", err)
+					}
+					_, err = env.counts.Post(env.ctx, count.ID)
+				}
+				if tt.wantErr != nil {
+					tt.wantErr(t, err)
+				} else if err != nil {
+					t.Fatalf("post: %v", err)
+				}
+
+				got, err := env.counts.GetByID(env.ctx, count.ID)
+				if err != nil {
+					t.Fatalf("get stock count: %v", err)
+				}
+				if got.Status != tt.wantStatus {
+					t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+				}
+				if (got.PostedAt != nil) != (tt.wantStatus == models.StockCountPosted) {
+					t.Errorf("posted_at = %v with status %s", got.PostedAt, got.Status)
+				}
+
+				for i, id := range productIDs {
+					if stock := env.stockOf(t, id); stock != tt.wantStock[i] {
+						t.Errorf("product %d stock = %d, want %d", id, stock, tt.wantStock[i])
+					}
+
+					// Selisih tercatat di ledger dengan referensi sesinya; baris tanpa selisih tidak dicatat.
+					var opname int
+					for _, m := range env.history(t, id) {
+						if m.Reason != models.StockOpname {
+							continue
+						}
+						if m.Reference != models.Reference("stock_count", count.ID) {
+							t.Errorf("opname movement reference = %q, want stock_count:%d", m.Reference, count.ID)
+						}
+						opname += m.Delta
+					}
+					if opname != tt.wantOpnames[i] {
+						t.Errorf("product %d opname delta = %d, want %d", id, opname, tt.wantOpnames[i])
+					}
+				}
+
+				discrepancies, err := env.stock.Reconcile(env.ctx)
+				if err != nil {
+					t.Fatalf("reconcile: %v", err)
+				}
+				if len(discrepancies) > 0 {
+					t.Errorf("stock does not match the ledger: %+v", discrepancies)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"task-session-1/models"
+	"task-session-1/repositories"
+)
+
+// StockService adalah struct yang menyimpan dependency untuk ledger dan penyesuaian stok.
+// productRepo dan variantRepo digunakan untuk memastikan produk atau varian yang diminta ada.
+type StockService struct {
+	movementRepo repositories.StockMovementStore
+	productRepo  repositories.ProductStore
+	variantRepo  repositories.VariantStore
+}
+
+// NewStockService adalah konstruktor untuk membuat instance StockService.
+func NewStockService(movementRepo repositories.StockMovementStore, productRepo repositories.ProductStore, variantRepo repositories.VariantStore) *StockService {
+	return &StockService{movementRepo: movementRepo, productRepo: productRepo, variantRepo: variantRepo}
+}
+
+// Adjust menerapkan penyesuaian stok ke produk aktif productID, atau ke variannya jika adj.VariantID diisi,
+// lalu mengembalikan pergerakan stok yang dicatat di ledger. Seperti checkout, produk yang punya varian
+// disesuaikan per varian sehingga variant_id wajib diisi.
+// Mengembalikan NotFoundError jika produk atau varian tidak ditemukan, dan InsufficientStockError jika
+// penyesuaian akan membuat stok negatif.
+func (s *StockService) Adjust(ctx context.Context, productID int, adj models.StockAdjustment) (*models.StockMovement, error) {
+	if err := adj.Validate(); err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID, models.ProductOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, &models.NotFoundError{Resource: "product", ID: productID}
+	}
+
+	variants, err := s.variantRepo.ListByProducts(ctx, []int{productID})
+	if err != nil {
+		return nil, err
+	}
+	if adj.VariantID == nil && len(variants[productID]) > 0 {
+		return nil, models.NewValidationError("variant_id", "is required for products with variants")
+	}
+	if adj.VariantID != nil && !slices.ContainsFunc(variants[productID], func(v models.ProductVariant) bool { return v.ID == *adj.VariantID }) {
+		return nil, &models.NotFoundError{Resource: "variant", ID: *adj.VariantID}
+	}
+
+	movement := &models.StockMovement{
+		ProductID: productID,
+		VariantID: adj.VariantID,
+		Delta:     adj.Delta,
+		Reason:    adj.Reason,
+	}
+	if err := s.movementRepo.Adjust(ctx, movement); err != nil {
+		return nil, err
+	}
+	return movement, nil
+}
+
+// History mengambil satu halaman riwayat stok produk filter.ProductID.
+// Riwayat produk yang sudah dihapus tetap bisa dibaca. Mengembalikan NotFoundError jika produk tidak ada.
+func (s *StockService) History(ctx context.Context, filter models.StockMovementFilter) (*models.Page[models.StockMovement], error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, filter.ProductID, models.ProductOptions{IncludeDeleted: true})
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, &models.NotFoundError{Resource: "product", ID: filter.ProductID}
+	}
+
+	return s.movementRepo.ListByProduct(ctx, filter)
+}
+
+// LowStock mengambil produk dan varian aktif yang stoknya sudah mencapai batas pemesanan ulang.
+// Produk dengan reorder_level 0 tidak pernah masuk daftar.
+func (s *StockService) LowStock(ctx context.Context, filter models.LowStockFilter) ([]models.LowStockItem, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.productRepo.ListLowStock(ctx, filter)
+}
+
+// Reconcile mengembalikan produk dan varian yang stoknya tidak sama dengan jumlah delta di ledger.
+// Hasil kosong berarti ledger konsisten.
+func (s *StockService) Reconcile(ctx context.Context) ([]models.StockDiscrepancy, error) {
+	return s.movementRepo.Reconcile(ctx)
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"slices"
+	"sync"
+	"task-session-1/models"
+	"testing"
+)
+
+func TestStockAdjust(t *testing.T) {
+	// Produk A berstok 10 tanpa varian dan produk B berstok 7 dengan varian S berstok 4. Penyesuaian ditujukan
+	// ke produk sku, atau ke varian S jika variant diisi; wantStock adalah stok tujuan setelah penyesuaian.
+	tests := []struct {
+		name      string
+		sku       string
+		variant   bool
+		adj       models.StockAdjustment
+		wantStock int
+		wantErr   func(t *testing.T, err error)
+	}{
+		{
+			name:      "restock adds to product stock",
+			sku:       "A",
+			adj:       models.StockAdjustment{Delta: 5, Reason: models.StockRestock},
+			wantStock: 15,
+		},
+		{
+			name:      "damage may empty the stock",
+			sku:       "A",
+			adj:       models.StockAdjustment{Delta: -10, Reason: models.StockDamage},
+			wantStock: 0,
+		},
+		{
+			name:      "stock must not become negative",
+			sku:       "A",
+			adj:       models.StockAdjustment{Delta: -11, Reason: models.StockTheft},
+			wantStock: 10,
+			wantErr: func(t *testing.T, err error) {
+				stockErr := wantError[models.InsufficientStockError](t, err)
+				want := models.InsufficientStockError{ProductID: 1, Requested: 11, Available: 10}
+				if *stockErr != want {
+					t.Errorf("error = %+v, want %+v", *stockErr, want)
+				}
+			},
+		},
+		{
+			name:      "correction of a variant",
+			sku:       "B",
+			variant:   true,
+			adj:       models.StockAdjustment{Delta: -3, Reason: models.StockCorrection},
+			wantStock: 1,
+		},
+		{
+			name:      "variant stock must not become negative",
+			sku:       "B",
+			variant:   true,
+			adj:       models.StockAdjustment{Delta: -5, Reason: models.StockDamage},
+			wantStock: 4,
+			wantErr: func(t *testing.T, err error) {
+				stockErr := wantError[models.InsufficientStockError](t, err)
+				want := models.InsufficientStockError{ProductID: 2, VariantID: 1, Requested: 5, Available: 4}
+				if *stockErr != want {
+					t.Errorf("error = %+v, want %+v", *stockErr, want)
+				}
+			},
+		},
+		{
+			name:      "variant_id is required for products with variants",
+			sku:       "B",
+			adj:       models.StockAdjustment{Delta: 1, Reason: models.StockRestock},
+			wantStock: 7,
+			wantErr:   func(t *testing.T, err error) { wantFieldError(t, err, "variant_id") },
+		},
+		{
+			name:      "variant of another product",
+			sku:       "A",
+			variant:   true,
+			adj:       models.StockAdjustment{Delta: 1, Reason: models.StockRestock},
+			wantStock: 4,
+			wantErr:   func(t *testing.T, err error) { wantError[models.NotFoundError](t, err) },
+		},
+	}
+
+	for _, backend := range testBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				env := backend.env(t)
+				categoryID := env.category(t, "Drinks")
+				ids := map[string]int{"A": env.product(t, categoryID, "A", 10), "B": env.product(t, categoryID, "B", 7)}
+				variantID := env.variant(t, ids["B"], "S", 4)
+
+				id, adj := ids[tt.sku], tt.adj
+				if tt.variant {
+					adj.VariantID = &variantID
+				}
+				stock := func() int {
+					if tt.variant {
+						return env.variantStockOf(t, ids["B"], variantID)
+					}
+					return env.stockOf(t, id)
+				}
+
+				movement, err := env.stock.Adjust(env.ctx, id, adj)
+				if tt.wantErr != nil {
+					tt.wantErr(t, err)
+				} else if err != nil {
+					t.Fatalf("adjust: %v", err)
+				}
+
+				if got := stock(); got != tt.wantStock {
+					t.Errorf("stock = %d, want %d", got, tt.wantStock)
+				}
+				if got := env.stockOf(t, ids["B"]); got != 7 {
+					t.Errorf("product B stock = %d, want 7", got)
+				}
+
+				// Penyesuaian yang berhasil tercatat sebagai baris ledger terakhir; yang gagal tidak mencatat apa pun.
+				history := env.history(t, id)
+				last := history[len(history)-1]
+				if movement == nil {
+					if last.Reason == adj.Reason {
+						t.Errorf("failed adjustment was recorded: %+v", last)
+					}
+					return
+				}
+				if last.ID != movement.ID || last.Delta != adj.Delta || last.StockAfter != tt.wantStock || last.Reason != adj.Reason {
+					t.Errorf("ledger row = %+v, want delta %d, stock_after %d, reason %s", last, adj.Delta, tt.wantStock, adj.Reason)
+				}
+				if (last.VariantID != nil) != tt.variant {
+					t.Errorf("ledger row variant_id = %v, want variant %v", last.VariantID, tt.variant)
+				}
+				if movement.StockAfter != tt.wantStock {
+					t.Errorf("movement stock_after = %d, want %d", movement.StockAfter, tt.wantStock)
+				}
+			})
+		}
+	}
+}
+
+func TestStockAdjustConcurrent(t *testing.T) {
+	// Penyesuaian yang berjalan bersamaan tidak boleh membuat stok negatif.
+	const stock, adjustments = 5, 12
+	for _, backend := range testBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			env := backend.env(t)
+			id := env.product(t, env.category(t, "Drinks"), "A", stock)
+
+			var wg sync.WaitGroup
+			errs := make(chan error, adjustments)
+			for range adjustments {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := env.stock.Adjust(env.ctx, id, models.StockAdjustment{Delta: -1, Reason: models.StockDamage})
+					errs <- err
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			damaged := 0
+			for err := range errs {
+				if err == nil {
+					damaged++
+					continue
+				}
+				wantError[models.InsufficientStockError](t, err)
+			}
+			if damaged != stock {
+				t.Errorf("damaged %d, want %d", damaged, stock)
+			}
+			if got := env.stockOf(t, id); got != 0 {
+				t.Errorf("stock = %d, want 0", got)
+			}
+			discrepancies, err := env.stock.Reconcile(env.ctx)
+			if err != nil {
+				t.Fatalf("reconcile: %v", err)
+			}
+			if len(discrepancies) > 0 {
+				t.Errorf("stock does not match the ledger: %+v", discrepancies)
+			}
+		})
+	}
+}
+
+func TestStockLowStock(t *testing.T) {
+	// lowStock adalah baris daftar stok menipis yang diharapkan; size kosong berarti baris produk tanpa varian.
+	type lowStock struct {
+		sku   string
+		size  string
+		stock int
+	}
+
+	tests := []struct {
+		name   string
+		drinks bool
+		want   []lowStock
+	}{
+		{
+			name: "all categories",
+			want: []lowStock{{sku: "A", stock: 3}, {sku: "D", size: "S", stock: 5}, {sku: "F", stock: 1}},
+		},
+		{
+			name:   "one category",
+			drinks: true,
+			want:   []lowStock{{sku: "A", stock: 3}, {sku: "D", size: "S", stock: 5}},
+		},
+	}
+
+	for _, backend := range testBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				env := backend.env(t)
+				drinks := env.category(t, "Drinks")
+				snacks := env.category(t, "Snacks")
+
+				// A di bawah batas, B di atas batas, dan C tanpa batas. D punya varian sehingga stok produknya
+				// diabaikan dan yang diperiksa adalah stok tiap varian. E sudah dihapus, dan F ada di kategori lain.
+				products := []struct {
+					sku          string
+					categoryID   int
+					stock        int
+					reorderLevel int
+				}{
+					{"A", drinks, 3, 5},
+					{"B", drinks, 10, 5},
+					{"C", drinks, 0, 0},
+					{"D", drinks, 1, 5},
+					{"E", drinks, 1, 5},
+					{"F", snacks, 1, 5},
+				}
+				ids := make(map[string]int)
+				skus := make(map[int]string)
+				for _, p := range products {
+					ids[p.sku] = env.product(t, p.categoryID, p.sku, p.stock)
+					skus[ids[p.sku]] = p.sku
+					if p.reorderLevel > 0 {
+						env.reorderAt(t, ids[p.sku], p.reorderLevel)
+					}
+				}
+				env.variant(t, ids["D"], "S", 5)
+				env.variant(t, ids["D"], "M", 6)
+				if err := env.products.Delete(env.ctx, ids["E"]); err != nil {
+					t.Fatalf("delete product: %v", err)
+				}
+
+				filter := models.LowStockFilter{}
+				if tt.drinks {
+					filter.CategoryID = drinks
+				}
+				items, err := env.stock.LowStock(env.ctx, filter)
+				if err != nil {
+					t.Fatalf("low stock: %v", err)
+				}
+
+				var got []lowStock
+				for _, item := range items {
+					got = append(got, lowStock{sku: skus[item.ProductID], size: item.VariantName, stock: item.Stock})
+					if item.ReorderLevel != 5 {
+						t.Errorf("%s reorder level = %d, want 5", item.Name, item.ReorderLevel)
+					}
+					if (item.VariantID != nil) != (item.VariantName != "") {
+						t.Errorf("%s variant_id = %v with variant_name %q", item.Name, item.VariantID, item.VariantName)
+					}
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("low stock = %+v, want %+v", got, tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"task-session-1/models"
+	"task-session-1/repositories"
+)
+
+// SupplierService adalah struct yang menyimpan dependency untuk operasi supplier.
+type SupplierService struct {
+	repo repositories.SupplierStore
+}
+
+// NewSupplierService adalah konstruktor untuk membuat instance SupplierService.
+func NewSupplierService(repo repositories.SupplierStore) *SupplierService {
+	return &SupplierService{repo: repo}
+}
+
+// GetAll mengambil satu halaman supplier sesuai filter, pengurutan, dan paginasi.
+func (s *SupplierService) GetAll(ctx context.Context, filter models.SupplierFilter) (*models.Page[models.Supplier], error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAll(ctx, filter)
+}
+
+// Create membuat supplier baru setelah field-nya divalidasi.
+func (s *SupplierService) Create(ctx context.Context, supplier *models.Supplier) error {
+	if err := supplier.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Create(ctx, supplier)
+}
+
+// GetByID mengambil satu supplier berdasarkan ID. Mengembalikan NotFoundError jika supplier tidak ditemukan.
+func (s *SupplierService) GetByID(ctx context.Context, id int) (*models.Supplier, error) {
+	supplier, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if supplier == nil {
+		return nil, &models.NotFoundError{Resource: "supplier", ID: id}
+	}
+	return supplier, nil
+}
+
+// Update memperbarui data supplier setelah field-nya divalidasi.
+func (s *SupplierService) Update(ctx context.Context, supplier *models.Supplier) error {
+	if err := supplier.Validate(); err != nil {
+		return err
+	}
+	return s.repo.Update(ctx, supplier)
+}
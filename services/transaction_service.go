@@ -1,23 +1,63 @@
 package services
 
 import (
+	"context"
+	"log/slog"
 	"task-session-1/models"
+	"task-session-1/notify"
 	"task-session-1/repositories"
 	"time"
 )
 
+// lowStockNotifyTimeout adalah batas waktu pengiriman peringatan stok menipis setelah checkout.
+const lowStockNotifyTimeout = 30 * time.Second
+
 type TransactionService struct {
-	transactionRepo *repositories.TransactionRepository
+	transactionRepo repositories.TransactionStore
+	notifier        notify.Notifier
+}
+
+// NewTransactionService adalah konstruktor untuk membuat instance TransactionService.
+// notifier menerima peringatan saat checkout membuat stok turun ke batas pemesanan ulang.
+func NewTransactionService(transactionRepo repositories.TransactionStore, notifier notify.Notifier) *TransactionService {
+	return &TransactionService{transactionRepo: transactionRepo, notifier: notifier}
 }
 
-func NewTransactionService(transactionRepo *repositories.TransactionRepository) *TransactionService {
-	return &TransactionService{transactionRepo: transactionRepo}
+func (s *TransactionService) Checkout(ctx context.Context, items []models.CheckoutItem, useLock bool) (*models.Transaction, error) {
+	// Validasi payload checkout sebelum menyentuh stok.
+	req := models.CheckoutRequest{Items: items}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.transactionRepo.CreateTransaction(ctx, items)
+	if err != nil {
+		return nil, err
+	}
+
+	// Peringatan dikirim di background agar webhook yang lambat tidak menahan response checkout.
+	// Transaksi sudah tersimpan, sehingga kegagalan pengiriman hanya dicatat di log.
+	if len(transaction.LowStock) > 0 {
+		go s.notifyLowStock(context.WithoutCancel(ctx), transaction.LowStock)
+	}
+	return transaction, nil
 }
 
-func (s *TransactionService) Checkout(items []models.CheckoutItem, useLock bool) (*models.Transaction, error) {
-	return s.transactionRepo.CreateTransaction(items)
+// notifyLowStock mengirim peringatan stok menipis ke notifier dan mencatat kegagalannya.
+func (s *TransactionService) notifyLowStock(ctx context.Context, events []models.LowStockEvent) {
+	ctx, cancel := context.WithTimeout(ctx, lowStockNotifyTimeout)
+	defer cancel()
+
+	if err := s.notifier.NotifyLowStock(ctx, events); err != nil {
+		slog.ErrorContext(ctx, "failed to send low stock notification", "error", err, "transaction_id", events[0].TransactionID)
+	}
 }
 
-func (s *TransactionService) GetReport(startDate, endDate time.Time) (*models.ReportResponse, error) {
-	return s.transactionRepo.GetReport(startDate, endDate)
+// GetReport menghitung laporan penjualan dari tanggal startDate sampai endDate (inklusif).
+// Tanggal dibaca di zona waktu masing-masing, sehingga rentangnya dimulai tengah malam startDate
+// dan berakhir tepat sebelum tengah malam hari setelah endDate di zona waktu bisnis.
+func (s *TransactionService) GetReport(ctx context.Context, startDate, endDate time.Time) (*models.ReportResponse, error) {
+	from := time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
+	to := time.Date(endDate.Year(), endDate.Month(), endDate.Day()+1, 0, 0, 0, 0, endDate.Location())
+	return s.transactionRepo.GetReport(ctx, from, to)
 }
@@ -0,0 +1,334 @@
+package services
+
+import (
+	"slices"
+	"sync"
+	"task-session-1/models"
+	"testing"
+	"time"
+)
+
+func TestCheckout(t *testing.T) {
+	// lowStock adalah peringatan stok menipis yang diharapkan; variantID 0 berarti peringatan untuk produk.
+	type lowStock struct {
+		productID, variantID, stock int
+	}
+
+	// Produk dibuat berurutan dengan stok awal stocks dan batas pemesanan ulang reorderLevel, sehingga ID-nya 1, 2,
+	// dan seterusnya. Varian dibuat untuk produk 1 dengan stok awal variants dan ukuran dari variantSizes, sehingga
+	// ID-nya juga 1, 2, dan seterusnya.
+	tests := []struct {
+		name             string
+		stocks           []int
+		reorderLevel     int
+		variants         []int
+		items            []models.CheckoutItem
+		wantStock        []int
+		wantVariantStock []int
+		wantLowStock     []lowStock
+		wantErr          func(t *testing.T, err error)
+	}{
+		{
+			name:      "decrements stock of every item",
+			stocks:    []int{10, 5},
+			items:     []models.CheckoutItem{{ProductID: 1, Quantity: 3}, {ProductID: 2, Quantity: 5}},
+			wantStock: []int{7, 0},
+		},
+		{
+			name:      "same product twice",
+			stocks:    []int{10},
+			items:     []models.CheckoutItem{{ProductID: 1, Quantity: 4}, {ProductID: 1, Quantity: 6}},
+			wantStock: []int{0},
+		},
+		{
+			name:      "insufficient stock rolls back every item",
+			stocks:    []int{10, 5},
+			items:     []models.CheckoutItem{{ProductID: 1, Quantity: 3}, {ProductID: 2, Quantity: 6}},
+			wantStock: []int{10, 5},
+			wantErr: func(t *testing.T, err error) {
+				stockErr := wantError[models.InsufficientStockError](t, err)
+				want := models.InsufficientStockError{ProductID: 2, Requested: 6, Available: 5}
+				if *stockErr != want {
+					t.Errorf("error = %+v, want %+v", *stockErr, want)
+				}
+			},
+		},
+		{
+			name:      "insufficient stock counts earlier items of the same product",
+			stocks:    []int{10},
+			items:     []models.CheckoutItem{{ProductID: 1, Quantity: 6}, {ProductID: 1, Quantity: 6}},
+			wantStock: []int{10},
+			wantErr: func(t *testing.T, err error) {
+				if stockErr := wantError[models.InsufficientStockError](t, err); stockErr.Available != 4 {
+					t.Errorf("available = %d, want 4", stockErr.Available)
+				}
+			},
+		},
+		{
+			name:      "unknown product rolls back every item",
+			stocks:    []int{10},
+			items:     []models.CheckoutItem{{ProductID: 1, Quantity: 3}, {ProductID: 99, Quantity: 1}},
+			wantStock: []int{10},
+			wantErr: func(t *testing.T, err error) {
+				wantError[models.NotFoundError](t, err)
+			},
+		},
+		{
+			name:             "variant stock is decremented instead of product stock",
+			stocks:           []int{10, 5},
+			variants:         []int{4, 6},
+			items:            []models.CheckoutItem{{ProductID: 1, VariantID: 2, Quantity: 5}, {ProductID: 2, Quantity: 1}},
+			wantStock:        []int{10, 4},
+			wantVariantStock: []int{4, 1},
+		},
+		{
+			name:             "insufficient variant stock",
+			stocks:           []int{10},
+			variants:         []int{4, 6},
+			items:            []models.CheckoutItem{{ProductID: 1, VariantID: 2, Quantity: 1}, {ProductID: 1, VariantID: 1, Quantity: 5}},
+			wantStock:        []int{10},
+			wantVariantStock: []int{4, 6},
+			wantErr: func(t *testing.T, err error) {
+				stockErr := wantError[models.InsufficientStockError](t, err)
+				want := models.InsufficientStockError{ProductID: 1, VariantID: 1, Requested: 5, Available: 4}
+				if *stockErr != want {
+					t.Errorf("error = %+v, want %+v", *stockErr, want)
+				}
+			},
+		},
+		{
+			name:             "variant_id is required for products with variants",
+			stocks:           []int{10, 5},
+			variants:         []int{4},
+			items:            []models.CheckoutItem{{ProductID: 2, Quantity: 1}, {ProductID: 1, Quantity: 1}},
+			wantStock:        []int{10, 5},
+			wantVariantStock: []int{4},
+			wantErr: func(t *testing.T, err error) {
+				wantFieldError(t, err, "items[1].variant_id")
+			},
+		},
+		{
+			name:         "crossing the reorder level emits one low stock event",
+			stocks:       []int{10, 20},
+			reorderLevel: 5,
+			items:        []models.CheckoutItem{{ProductID: 1, Quantity: 6}, {ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}},
+			wantStock:    []int{3, 19},
+			wantLowStock: []lowStock{{productID: 1, stock: 4}},
+		},
+		{
+			name:         "stock already below the reorder level emits no event",
+			stocks:       []int{3},
+			reorderLevel: 5,
+			items:        []models.CheckoutItem{{ProductID: 1, Quantity: 1}},
+			wantStock:    []int{2},
+		},
+		{
+			name:      "reorder level 0 emits no event",
+			stocks:    []int{10},
+			items:     []models.CheckoutItem{{ProductID: 1, Quantity: 10}},
+			wantStock: []int{0},
+		},
+		{
+			name:             "variant crossing the reorder level of its product emits an event",
+			stocks:           []int{10},
+			reorderLevel:     5,
+			variants:         []int{6, 6},
+			items:            []models.CheckoutItem{{ProductID: 1, VariantID: 2, Quantity: 2}},
+			wantStock:        []int{10},
+			wantVariantStock: []int{6, 4},
+			wantLowStock:     []lowStock{{productID: 1, variantID: 2, stock: 4}},
+		},
+	}
+	variantSizes := []string{"S", "M", "L"}
+
+	for _, backend := range testBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				env := backend.env(t)
+				categoryID := env.category(t, "Drinks")
+				var ids []int
+				for i, stock := range tt.stocks {
+					ids = append(ids, env.product(t, categoryID, string(rune('A'+i)), stock))
+					if tt.reorderLevel > 0 {
+						env.reorderAt(t, ids[i], tt.reorderLevel)
+					}
+				}
+				var variantIDs []int
+				for i, stock := range tt.variants {
+					variantIDs = append(variantIDs, env.variant(t, ids[0], variantSizes[i], stock))
+				}
+
+				transaction, err := env.transactions.Checkout(env.ctx, tt.items, false)
+				if tt.wantErr != nil {
+					tt.wantErr(t, err)
+				} else if err != nil {
+					t.Fatalf("checkout: %v", err)
+				}
+
+				// Setiap penjualan tercatat di ledger dengan referensi transaksinya; checkout yang gagal tidak mencatat apa pun.
+				// Penjualan varian dicatat untuk variannya, bukan untuk produknya.
+				soldByVariant := make(map[int]int)
+				for i, id := range ids {
+					if got := env.stockOf(t, id); got != tt.wantStock[i] {
+						t.Errorf("product %d stock = %d, want %d", id, got, tt.wantStock[i])
+					}
+
+					var sold int
+					for _, m := range env.history(t, id) {
+						if m.Reason != models.StockSale {
+							continue
+						}
+						if transaction == nil || m.Reference != models.Reference("transaction", transaction.ID) {
+							t.Errorf("sale movement %+v does not reference the checkout", m)
+						}
+						if m.VariantID != nil {
+							soldByVariant[*m.VariantID] -= m.Delta
+							continue
+						}
+						sold -= m.Delta
+					}
+					if want := tt.stocks[i] - tt.wantStock[i]; sold != want {
+						t.Errorf("product %d sold %d in the ledger, want %d", id, sold, want)
+					}
+				}
+				for i, id := range variantIDs {
+					if got := env.variantStockOf(t, ids[0], id); got != tt.wantVariantStock[i] {
+						t.Errorf("variant %d stock = %d, want %d", id, got, tt.wantVariantStock[i])
+					}
+					if want := tt.variants[i] - tt.wantVariantStock[i]; soldByVariant[id] != want {
+						t.Errorf("variant %d sold %d in the ledger, want %d", id, soldByVariant[id], want)
+					}
+				}
+
+				if transaction != nil {
+					var quantities []int
+					for _, d := range transaction.Details {
+						quantities = append(quantities, d.Quantity)
+					}
+					var want []int
+					for _, item := range tt.items {
+						want = append(want, item.Quantity)
+					}
+					if !slices.Equal(quantities, want) {
+						t.Errorf("detail quantities = %v, want %v", quantities, want)
+					}
+
+					for i, d := range transaction.Details {
+						item := tt.items[i]
+						switch {
+						case item.VariantID == 0 && (d.VariantID != nil || d.VariantName != ""):
+							t.Errorf("detail %d has variant %v %q, want none", i, d.VariantID, d.VariantName)
+						case item.VariantID != 0 && (d.VariantID == nil || *d.VariantID != item.VariantID):
+							t.Errorf("detail %d variant_id = %v, want %d", i, d.VariantID, item.VariantID)
+						case item.VariantID != 0 && d.VariantName != variantSizes[item.VariantID-1]:
+							t.Errorf("detail %d variant_name = %q, want %q", i, d.VariantName, variantSizes[item.VariantID-1])
+						}
+					}
+
+					// Peringatan hanya muncul untuk penjualan yang membuat stok turun melewati batas pemesanan ulang.
+					var events []lowStock
+					for _, e := range transaction.LowStock {
+						event := lowStock{productID: e.ProductID, stock: e.Stock}
+						if e.VariantID != nil {
+							event.variantID = *e.VariantID
+						}
+						events = append(events, event)
+						if e.TransactionID != transaction.ID {
+							t.Errorf("low stock event transaction_id = %d, want %d", e.TransactionID, transaction.ID)
+						}
+					}
+					if !slices.Equal(events, tt.wantLowStock) {
+						t.Errorf("low stock events = %+v, want %+v", events, tt.wantLowStock)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestCheckoutConcurrent(t *testing.T) {
+	// Checkout yang berjalan bersamaan tidak boleh menjual stok yang sama dua kali.
+	const stock, buyers = 5, 12
+	for _, backend := range testBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			env := backend.env(t)
+			id := env.product(t, env.category(t, "Drinks"), "A", stock)
+
+			var wg sync.WaitGroup
+			errs := make(chan error, buyers)
+			for range buyers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := env.transactions.Checkout(env.ctx, []models.CheckoutItem{{ProductID: id, Quantity: 1}}, false)
+					errs <- err
+				}()
+			}
+			wg.Wait()
+			close(errs)
+
+			sold := 0
+			for err := range errs {
+				if err == nil {
+					sold++
+					continue
+				}
+				wantError[models.InsufficientStockError](t, err)
+			}
+			if sold != stock {
+				t.Errorf("sold %d, want %d", sold, stock)
+			}
+			if got := env.stockOf(t, id); got != 0 {
+				t.Errorf("stock = %d, want 0", got)
+			}
+			for _, m := range env.history(t, id) {
+				if m.StockAfter < 0 {
+					t.Errorf("ledger recorded negative stock: %+v", m)
+				}
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	// Zona waktu di timur UTC dipilih sehingga checkout terjadi tepat setelah tengah malam lokal, saat tanggal UTC-nya
+	// masih hari sebelumnya. Transaksi harus masuk laporan hari ini di zona tersebut, bukan kemarin.
+	now := time.Now().UTC()
+	sinceMidnight := int(now.Sub(now.Truncate(24 * time.Hour)).Seconds())
+	location := time.FixedZone("test", 24*60*60-sinceMidnight)
+	today := now.In(location)
+	yesterday := today.AddDate(0, 0, -1)
+
+	tests := []struct {
+		name             string
+		start, end       time.Time
+		wantTransactions int
+	}{
+		{name: "today", start: today, end: today, wantTransactions: 1},
+		{name: "yesterday", start: yesterday, end: yesterday, wantTransactions: 0},
+		{name: "yesterday until today", start: yesterday, end: today, wantTransactions: 1},
+	}
+
+	for _, backend := range testBackends {
+		for _, tt := range tests {
+			t.Run(backend.name+"/"+tt.name, func(t *testing.T) {
+				env := backend.env(t)
+				id := env.product(t, env.category(t, "Drinks"), "A", 10)
+				if _, err := env.transactions.Checkout(env.ctx, []models.CheckoutItem{{ProductID: id, Quantity: 2}}, false); err != nil {
+					t.Fatalf("checkout: %v", err)
+				}
+
+				report, err := env.transactions.GetReport(env.ctx, tt.start, tt.end)
+				if err != nil {
+					t.Fatalf("report: %v", err)
+				}
+				if report.TotalTransaksi != tt.wantTransactions {
+					t.Errorf("transactions = %d, want %d", report.TotalTransaksi, tt.wantTransactions)
+				}
+				if want := tt.wantTransactions * 2000; report.TotalRevenue != want {
+					t.Errorf("revenue = %d, want %d", report.TotalRevenue, want)
+				}
+			})
+		}
+	}
+}
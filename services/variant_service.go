@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"task-session-1/models"
+	"task-session-1/repositories"
+)
+
+// VariantService adalah struct yang menyimpan dependency untuk operasi varian produk.
+// productRepo digunakan untuk memastikan produk pemilik varian ada dan belum dihapus.
+type VariantService struct {
+	variantRepo repositories.VariantStore
+	productRepo repositories.ProductStore
+}
+
+// NewVariantService adalah konstruktor untuk membuat instance VariantService.
+func NewVariantService(variantRepo repositories.VariantStore, productRepo repositories.ProductStore) *VariantService {
+	return &VariantService{variantRepo: variantRepo, productRepo: productRepo}
+}
+
+// List mengambil semua varian aktif milik produk productID, diurutkan berdasarkan ID.
+// Mengembalikan NotFoundError jika produk tidak ditemukan.
+func (s *VariantService) List(ctx context.Context, productID int) ([]models.ProductVariant, error) {
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	variants, err := s.variantRepo.ListByProducts(ctx, []int{productID})
+	if err != nil {
+		return nil, err
+	}
+	if variants[productID] == nil {
+		return []models.ProductVariant{}, nil
+	}
+	return variants[productID], nil
+}
+
+// Create membuat varian baru untuk produk productID.
+// Opsi dirapikan dengan NormalizeOptions lalu divalidasi, kemudian dibandingkan dengan varian lain
+// (lihat checkOptions). Mengembalikan NotFoundError jika produk tidak ditemukan.
+func (s *VariantService) Create(ctx context.Context, productID int, variant *models.ProductVariant) error {
+	variant.ID = 0
+	variant.ProductID = productID
+	variant.NormalizeOptions()
+	if err := variant.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return err
+	}
+	if err := s.checkOptions(ctx, variant); err != nil {
+		return err
+	}
+
+	return s.variantRepo.Create(ctx, variant)
+}
+
+// Update memperbarui opsi, SKU, dan harga varian variant.ID milik produk productID.
+// Stok varian hanya bisa diubah lewat penyesuaian stok, sehingga variant.Stock diabaikan dan diisi stok saat ini.
+// Mengembalikan NotFoundError jika produk atau varian tidak ditemukan, atau varian bukan milik produk tersebut.
+func (s *VariantService) Update(ctx context.Context, productID int, variant *models.ProductVariant) error {
+	variant.ProductID = productID
+	variant.Stock = 0
+	variant.NormalizeOptions()
+	if err := variant.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := s.get(ctx, productID, variant.ID); err != nil {
+		return err
+	}
+	if err := s.checkOptions(ctx, variant); err != nil {
+		return err
+	}
+
+	return s.variantRepo.Update(ctx, variant)
+}
+
+// Delete menghapus varian variantID milik produk productID secara soft delete.
+func (s *VariantService) Delete(ctx context.Context, productID, variantID int) error {
+	if _, err := s.get(ctx, productID, variantID); err != nil {
+		return err
+	}
+	return s.variantRepo.Delete(ctx, variantID)
+}
+
+// get mengambil varian aktif dan memastikan varian tersebut milik produk aktif productID.
+func (s *VariantService) get(ctx context.Context, productID, variantID int) (*models.ProductVariant, error) {
+	if err := s.checkProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	variant, err := s.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+	if variant == nil || variant.ProductID != productID {
+		return nil, &models.NotFoundError{Resource: "variant", ID: variantID}
+	}
+	return variant, nil
+}
+
+// checkProduct memastikan produk productID ada dan belum dihapus.
+func (s *VariantService) checkProduct(ctx context.Context, productID int) error {
+	product, err := s.productRepo.GetByID(ctx, productID, models.ProductOptions{})
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return &models.NotFoundError{Resource: "product", ID: productID}
+	}
+	return nil
+}
+
+// checkOptions memastikan varian memakai sumbu opsi yang sama dengan varian aktif lain dari produk yang sama
+// (ValidationError), dan kombinasi opsinya belum dipakai varian lain (ConflictError).
+func (s *VariantService) checkOptions(ctx context.Context, variant *models.ProductVariant) error {
+	siblings, err := s.variantRepo.ListByProducts(ctx, []int{variant.ProductID})
+	if err != nil {
+		return err
+	}
+
+	for _, other := range siblings[variant.ProductID] {
+		if other.ID == variant.ID {
+			continue
+		}
+		if !variant.SameAxes(&other) {
+			return models.NewValidationError("options",
+				"must use the same option names as the other variants: "+strings.Join(other.OptionNames(), ", "))
+		}
+		if variant.SameOptions(&other) {
+			return &models.ConflictError{
+				Message: "a variant with the same options already exists",
+				Details: map[string]interface{}{"variant_id": other.ID},
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"task-session-1/config"
+	"task-session-1/database"
+	"task-session-1/repositories"
+	"task-session-1/services"
+)
+
+// runStockCommand menjalankan subcommand stok. Saat ini hanya reconcile, yang memeriksa apakah stok setiap
+// produk dan varian sama dengan jumlah delta di ledger stock_movements. Selisih dicetak per baris, dan
+// command gagal (exit code bukan 0) jika ada selisih, sehingga bisa dipakai di cron atau pipeline.
+func runStockCommand(cfg *config.Config, args []string) error {
+	action := "reconcile"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	if action != "reconcile" {
+		return fmt.Errorf("unknown action %q, use reconcile", action)
+	}
+
+	db, err := database.InitDB(databaseConfig(cfg))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	service := services.NewStockService(repositories.NewStockMovementRepository(db),
+		repositories.NewProductRepository(db), repositories.NewVariantRepository(db))
+	discrepancies, err := service.Reconcile(context.Background())
+	if err != nil {
+		return err
+	}
+
+	for _, d := range discrepancies {
+		target := fmt.Sprintf("product %d", d.ProductID)
+		if d.VariantID != nil {
+			target += fmt.Sprintf(" variant %d", *d.VariantID)
+		}
+		fmt.Printf("%-30s stock %d, ledger %d (diff %+d)\n", target, d.Stock, d.LedgerStock, d.Stock-d.LedgerStock)
+	}
+	if len(discrepancies) > 0 {
+		return fmt.Errorf("%d stock discrepancies found", len(discrepancies))
+	}
+	fmt.Println("stock matches the ledger")
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"task-session-1/config"
+	"task-session-1/database"
+	"task-session-1/repositories"
+	"task-session-1/repositories/memory"
+)
+
+// storage mengelompokkan semua repository yang dipakai aplikasi beserta koneksi database-nya.
+// db bernilai nil jika aplikasi berjalan dengan storage in-memory.
+type storage struct {
+	db           *database.DB
+	categories   repositories.CategoryStore
+	products     repositories.ProductStore
+	variants     repositories.VariantStore
+	transactions repositories.TransactionStore
+	movements    repositories.StockMovementStore
+	suppliers    repositories.SupplierStore
+	orders       repositories.PurchaseOrderStore
+	stockCounts  repositories.StockCountStore
+}
+
+// openStorage membuat repository sesuai konfigurasi STORAGE.
+// Untuk "database", koneksi dibuka dan migrasi dijalankan jika AUTO_MIGRATE aktif.
+// Untuk "memory", semua data disimpan di memori dan hilang saat aplikasi berhenti.
+func openStorage(cfg *config.Config) (*storage, error) {
+	switch cfg.Storage {
+	case config.StorageMemory:
+		log.Println("Using in-memory storage, data will not be persisted")
+		store := memory.NewStore()
+		return &storage{
+			categories:   memory.NewCategoryRepository(store),
+			products:     memory.NewProductRepository(store),
+			variants:     memory.NewVariantRepository(store),
+			transactions: memory.NewTransactionRepository(store),
+			movements:    memory.NewStockMovementRepository(store),
+			suppliers:    memory.NewSupplierRepository(store),
+			orders:       memory.NewPurchaseOrderRepository(store),
+			stockCounts:  memory.NewStockCountRepository(store),
+		}, nil
+
+	case config.StorageDatabase:
+		// Inisialisasi koneksi database menggunakan fungsi InitDB dari package database.
+		db, err := database.InitDB(databaseConfig(cfg))
+		if err != nil {
+			return nil, fmt.Errorf("initialize database: %w", err)
+		}
+
+		// Menjalankan migrasi skema yang belum diterapkan jika AUTO_MIGRATE aktif.
+		if cfg.AutoMigrate {
+			if err := database.Migrate(context.Background(), db); err != nil {
+				db.Close()
+				return nil, fmt.Errorf("run migrations: %w", err)
+			}
+		}
+
+		return &storage{
+			db:           db,
+			categories:   repositories.NewCategoryRepository(db),
+			products:     repositories.NewProductRepository(db),
+			variants:     repositories.NewVariantRepository(db),
+			transactions: repositories.NewTransactionRepository(db),
+			movements:    repositories.NewStockMovementRepository(db),
+			suppliers:    repositories.NewSupplierRepository(db),
+			orders:       repositories.NewPurchaseOrderRepository(db),
+			stockCounts:  repositories.NewStockCountRepository(db),
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown STORAGE %q, use %q or %q", cfg.Storage, config.StorageDatabase, config.StorageMemory)
+	}
+}
+
+// databaseConfig mengambil pengaturan koneksi dan connection pool dari konfigurasi aplikasi.
+func databaseConfig(cfg *config.Config) database.Config {
+	return database.Config{
+		Driver:          cfg.DBDriver,
+		ConnString:      cfg.DBConn,
+		MaxOpenConns:    cfg.DBMaxOpenConns,
+		MaxIdleConns:    cfg.DBMaxIdleConns,
+		ConnMaxLifetime: cfg.DBConnMaxLifetime,
+		ConnMaxIdleTime: cfg.DBConnMaxIdleTime,
+		QueryTimeout:    cfg.QueryTimeout,
+	}
+}
+
+// Close menutup koneksi database jika ada.
+func (s *storage) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}